@@ -3,33 +3,79 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
 
 const (
-	AccessTokenCookieName   = "access_token"
-	AccessTokenTTLInSeconds = 3600
-	CommentsCollection      = "comments"
-	RepliesCollection       = "replies"
-	CommonPaginationLength  = 12
-	LargePaginationLength   = 2 // TODO: Change later to 1200
-	UserDetailsCollection   = "user_details"
-	PostsCollection         = "posts"
-	UsersCollection         = "users"
+	AccessTokenCookieName          = "access_token"
+	AccessTokenTTLInSeconds        = 3600
+	BlocksCollection               = "blocks"
+	CloseFriendsCollection         = "close_friends"
+	CommentsCollection             = "comments"
+	DataExportClaimTimeout         = 30 * time.Minute
+	DataExportsCollection          = "data_exports"
+	RepliesCollection              = "replies"
+	CommonPaginationLength         = 12
+	ConversationsCollection        = "conversations"
+	DismissedSuggestionsCollection = "dismissed_suggestions"
+	FollowedByPreviewLength        = 2
+	FollowRequestsCollection       = "follow_requests"
+	FollowsCollection              = "follows"
+	HighlightsCollection           = "highlights"
+	JobLeasesCollection            = "job_leases"
+	LargePaginationLength          = 2 // TODO: Change later to 1200
+	MaxConversationMembers         = 32
+	MaxPinnedComments              = 3
+	MaxSuggestionsLength           = 50
+	MaxPurgeBatchSize              = 500
+	MaxAccountDeletionBatchSize    = 100
+	MaxDataExportBatchSize         = 20
+	MaxPaginationLength            = 50
+	MessagesCollection             = "messages"
+	UserDetailsCollection          = "user_details"
+	NotificationsCollection        = "notifications"
+	PostsCollection                = "posts"
+	StoriesCollection              = "stories"
+	StoryArchiveCollection         = "story_archive"
+	StoryTTLInSeconds              = 86400
+	StoryViewsCollection           = "story_views"
+	SimilarPostsLength             = 9
+	SavedCollectionsCollection     = "saved_collections"
+	SavedCollectionPostsCollection = "saved_collection_posts"
+	ThreadRepliesLength            = 3
+	TimelineFanOutLimit            = 5000
+	TimelineScanFactor             = 4
+	TimelinesCollection            = "timelines"
+	UsersCollection                = "users"
 )
 
 var (
 	AWSBucket         string
 	AccessTokenSecret string
-	ClientOrigin      string
-	MongoDBURI        string
-	MongoDBName       string
-	Port              string
-	IsDevelopment     = gin.Mode() == gin.DebugMode
-	IsProduction      = gin.Mode() == gin.ReleaseMode
-	IsTesting         = gin.Mode() == gin.TestMode
+	// Zero disables the scheduled deletion of the accounts whose owners asked for it
+	AccountDeletionInterval = 10 * time.Minute
+	ClientOrigin            string
+	CommentEditWindow       = 15 * time.Minute
+	// Zero disables the scheduled counter reconciliation
+	CounterReconciliationInterval time.Duration
+	CursorSecret                  string
+	// Zero disables the scheduled processing of the requested data exports
+	DataExportInterval = time.Minute
+	// The download links of data exports expire after this long, which S3 caps at 7 days
+	DataExportLinkTTL = 7 * 24 * time.Hour
+	// Soft deleted posts, comments and replies can be restored for this long and are purged afterward
+	DeletedContentRetention = 30 * 24 * time.Hour
+	// Zero disables the scheduled purge of soft deleted content
+	DeletedContentPurgeInterval = 24 * time.Hour
+	MongoDBURI                  string
+	MongoDBName                 string
+	Port                        string
+	IsDevelopment               = gin.Mode() == gin.DebugMode
+	IsProduction                = gin.Mode() == gin.ReleaseMode
+	IsTesting                   = gin.Mode() == gin.TestMode
 )
 
 func init() {
@@ -51,6 +97,17 @@ func init() {
 	AWSBucket = os.Getenv("AWS_BUCKET")
 	AccessTokenSecret = os.Getenv("ACCESS_TOKEN_SECRET")
 	ClientOrigin = os.Getenv("CLIENT_ORIGIN")
+	CursorSecret = os.Getenv("CURSOR_SECRET")
+	if CursorSecret == "" {
+		CursorSecret = AccessTokenSecret
+	}
+
+	AccountDeletionInterval = durationEnv("ACCOUNT_DELETION_INTERVAL", AccountDeletionInterval)
+	CommentEditWindow = durationEnv("COMMENT_EDIT_WINDOW", CommentEditWindow)
+	CounterReconciliationInterval = durationEnv("COUNTER_RECONCILIATION_INTERVAL", CounterReconciliationInterval)
+	DataExportInterval = durationEnv("DATA_EXPORT_INTERVAL", DataExportInterval)
+	DeletedContentPurgeInterval = durationEnv("DELETED_CONTENT_PURGE_INTERVAL", DeletedContentPurgeInterval)
+
 	MongoDBName = os.Getenv("MONGODB_NAME")
 	MongoDBURI = os.Getenv("MONGODB_URI")
 	Port = os.Getenv("PORT")
@@ -58,3 +115,18 @@ func init() {
 		Port = "5000"
 	}
 }
+
+// durationEnv parses the duration in the environment variable, or returns fallback when it is not set
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("%v: %v", name, err)
+	}
+
+	return duration
+}
@@ -106,6 +106,22 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	if user.DeletionRequestedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "This account is being deleted"})
+		return
+	}
+
+	// Logging in reactivates a deactivated account
+	if user.DeactivatedAt != nil {
+		err = models.ReactivateUser(ctx, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		user.DeactivatedAt = nil
+	}
+
 	accessToken, err := user.GenerateAccessToken()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func BlockUser(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	userIdParamValue := c.Param("_id")
+	userToBlockId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	if cliams.ID == userToBlockId {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "You cannot block yourself"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": userToBlockId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.CreateBlock(sessCtx, cliams.ID, userToBlockId)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func UnblockUser(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	userIdParamValue := c.Param("_id")
+	userToUnblockId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	_, err = models.DeleteBlock(ctx, cliams.ID, userToUnblockId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetBlockedUsers(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"blockerId": cliams.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline,
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"blockerId": 0, "userId": 0}},
+	)
+
+	blocksCollection := services.GetMongoDBCollection(config.BlocksCollection)
+	cursor, err := blocksCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	blocks := []bson.M{}
+	err = cursor.All(ctx, &blocks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(blocks, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SavedCollectionPostRequestBody struct {
+	PostID string `json:"postId" binding:"object_id"`
+}
+
+func CreateSavedCollection(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	savedCollection := &models.SavedCollection{}
+	messages := helpers.ValidateRequestBody(c, savedCollection)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	savedCollection.NormalizeFields(cliams.ID)
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err := savedCollectionsCollection.InsertOne(ctx, savedCollection)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "A collection with this name already exists"})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"collection": savedCollection})
+}
+
+func GetSavedCollections(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"userId": cliams.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": bson.M{"userId": 0}})
+
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	cursor, err := savedCollectionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	savedCollections := []bson.M{}
+	err = cursor.All(ctx, &savedCollections)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(savedCollections, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func UpdateSavedCollection(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	collectionIdParamValue := c.Param("_id")
+	collectionId, err := primitive.ObjectIDFromHex(collectionIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid collectionId", collectionIdParamValue)})
+		return
+	}
+
+	requestBody := &models.SavedCollection{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findSavedCollectionResult := models.FindSavedCollection(ctx, bson.M{"_id": collectionId, "userId": cliams.ID})
+	if findSavedCollectionResult.SavedCollection == nil {
+		c.JSON(findSavedCollectionResult.StatusCode, findSavedCollectionResult.ResponseBody)
+		return
+	}
+
+	savedCollection := findSavedCollectionResult.SavedCollection
+	savedCollection.Name = strings.TrimSpace(requestBody.Name)
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.UpdateByID(ctx, collectionId, bson.M{"$set": bson.M{"name": savedCollection.Name}})
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "A collection with this name already exists"})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	savedCollection.UserID = nil
+	c.JSON(http.StatusOK, gin.H{"collection": savedCollection})
+}
+
+func DeleteSavedCollection(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	collectionIdParamValue := c.Param("_id")
+	collectionId, err := primitive.ObjectIDFromHex(collectionIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid collectionId", collectionIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findSavedCollectionResult := models.FindSavedCollection(ctx, bson.M{"_id": collectionId, "userId": cliams.ID}, findOneOptions)
+	if findSavedCollectionResult.SavedCollection == nil {
+		c.JSON(findSavedCollectionResult.StatusCode, findSavedCollectionResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	// Posts in the collection remain in the user's saved posts
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+		_, err := savedCollectionPostsCollection.DeleteMany(sessCtx, bson.M{"collectionId": collectionId})
+		if err != nil {
+			return nil, err
+		}
+
+		savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+		_, err = savedCollectionsCollection.DeleteOne(sessCtx, bson.M{"_id": collectionId})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func AddPostToSavedCollection(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	collectionIdParamValue := c.Param("_id")
+	collectionId, err := primitive.ObjectIDFromHex(collectionIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid collectionId", collectionIdParamValue)})
+		return
+	}
+
+	requestBody := &SavedCollectionPostRequestBody{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	postId, err := primitive.ObjectIDFromHex(requestBody.PostID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", requestBody.PostID)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findSavedCollectionResult := models.FindSavedCollection(ctx, bson.M{"_id": collectionId, "userId": cliams.ID}, findOneOptions)
+	if findSavedCollectionResult.SavedCollection == nil {
+		c.JSON(findSavedCollectionResult.StatusCode, findSavedCollectionResult.ResponseBody)
+		return
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.AddPostToSavedCollection(sessCtx, cliams.ID, collectionId, postId)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func RemovePostFromSavedCollection(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	collectionIdParamValue := c.Param("_id")
+	collectionId, err := primitive.ObjectIDFromHex(collectionIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid collectionId", collectionIdParamValue)})
+		return
+	}
+
+	postIdParamValue := c.Param("postId")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findSavedCollectionResult := models.FindSavedCollection(ctx, bson.M{"_id": collectionId, "userId": cliams.ID}, findOneOptions)
+	if findSavedCollectionResult.SavedCollection == nil {
+		c.JSON(findSavedCollectionResult.StatusCode, findSavedCollectionResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.RemovePostFromSavedCollection(sessCtx, collectionId, postId)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetSavedCollectionPosts(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	collectionIdParamValue := c.Param("_id")
+	collectionId, err := primitive.ObjectIDFromHex(collectionIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid collectionId", collectionIdParamValue)})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findSavedCollectionResult := models.FindSavedCollection(ctx, bson.M{"_id": collectionId, "userId": cliams.ID}, findOneOptions)
+	if findSavedCollectionResult.SavedCollection == nil {
+		c.JSON(findSavedCollectionResult.StatusCode, findSavedCollectionResult.ResponseBody)
+		return
+	}
+
+	audienceStages, err := models.PostAudienceStages(ctx, cliams.ID, "userId")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	visibleAuthorStages, err := models.VisibleAuthorStages(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	params.ItemFilter = models.ExcludeHiddenPosts(bson.M{})
+	params.ItemStages = append(audienceStages, visibleAuthorStages...)
+
+	matchStage := bson.M{"$match": bson.M{"collectionId": collectionId}}
+	pipeline := append(bson.A{matchStage}, params.BucketStages("posts", config.PostsCollection, models.PostProjection)...)
+
+	savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	cursor, err := savedCollectionPostsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	posts := []bson.M{}
+	err = cursor.All(ctx, &posts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(posts, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
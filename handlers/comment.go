@@ -3,13 +3,14 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,6 +19,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type CommentUpdateRequestBody struct {
+	Message string `json:"message" binding:"required"`
+}
+
 func CreateComment(c *gin.Context) {
 	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
 	if !ok {
@@ -48,28 +53,73 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
-	update := bson.M{
-		"$push": bson.M{"comments": bson.M{"$each": bson.A{comment}, "$position": 0, "$slice": config.CommonPaginationLength}},
-		"$inc":  bson.M{"commentsCount": 1},
+	findOneOptions = options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "commentsAudience": 1, "commentsDisabled": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
 	}
-	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
-	updateOneResult, err := postsCollection.UpdateByID(ctx, comment.PostID, update)
+
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	if !authorizeCommenting(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	comment.IsHidden, err = matchesCommentFilters(ctx, findPostResult.Post.UserID, cliams.ID, comment.Message)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	if updateOneResult.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"message": "Post not found"})
+	comment.Mentions, err = models.FindMentions(ctx, comment.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
-	_, err = commentsCollection.InsertOne(ctx, comment)
+	session, err := services.GetMongoDBSession()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+		_, err := commentsCollection.InsertOne(sessCtx, comment)
+		if err != nil {
+			return nil, err
+		}
+
+		postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+		_, err = postsCollection.UpdateByID(sessCtx, comment.PostID, bson.M{"$inc": bson.M{"commentsCount": 1}})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, models.AddCommentToPreview(sessCtx, comment)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !comment.IsHidden {
+		err = models.CreateNotification(ctx, findPostResult.Post.UserID, cliams.ID, models.CommentNotification, comment.PostID)
+		if err != nil {
+			log.Println(err)
+		}
+
+		mentionedUserIds := models.GetMentionedUserIds(comment.Mentions, nil)
+		err = models.CreateNotifications(ctx, mentionedUserIds, cliams.ID, models.MentionNotification, comment.PostID)
+		if err != nil {
+			log.Println(err)
+		}
+	}
 
 	comment.SetUser(findUserResult.User)
 	c.JSON(http.StatusCreated, gin.H{"comment": comment})
@@ -98,18 +148,76 @@ func DeleteComment(c *gin.Context) {
 		return
 	}
 
-	findCommentResult := models.FindComment(ctx, bson.M{"_id": commentId})
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": commentId}))
 	if findCommentResult.Comment == nil {
 		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
 		return
 	}
 
-	if cliams.ID != findCommentResult.Comment.UserID {
+	findOneOptions := options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": findCommentResult.Comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	// The post owner moderates every comment on the post
+	if cliams.ID != findCommentResult.Comment.UserID && cliams.ID != findPostResult.Post.UserID {
 		c.JSON(http.StatusForbidden, gin.H{"message": "You are not allowed to delete this comment"})
 		return
 	}
 
-	findPostResult := models.FindPost(ctx, bson.M{"_id": findCommentResult.Comment.PostID})
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	// Only the replies of the comment are deleted with it
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return models.SoftDeleteComment(sessCtx, findCommentResult.Comment, cliams.ID)
+	}
+
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+// RestoreComment brings back a comment, with the replies deleted along with it, that the user deleted within
+// config.DeletedContentRetention
+func RestoreComment(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	commentIdParamValue := c.Param("_id")
+	commentId, err := primitive.ObjectIDFromHex(commentIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid commentId", commentIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	filter := models.RestorableFilter(cliams.ID)
+	filter["_id"] = commentId
+	findOneOptions := options.FindOne().SetProjection(bson.M{"postId": 1})
+	findCommentResult := models.FindComment(ctx, filter, findOneOptions)
+	if findCommentResult.Comment == nil {
+		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
+		return
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"_id": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": findCommentResult.Comment.PostID}), findOneOptions)
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
@@ -123,56 +231,148 @@ func DeleteComment(c *gin.Context) {
 	defer session.EndSession(ctx)
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		post := findPostResult.Post
+		return models.RestoreComment(sessCtx, findCommentResult.Comment)
+	}
 
-		repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
-		_, err := repliesCollection.DeleteMany(sessCtx, bson.M{"postId": post.ID})
-		if err != nil {
-			return nil, err
-		}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
-		_, err = commentsCollection.DeleteOne(sessCtx, bson.M{"_id": commentId})
-		if err != nil {
-			return nil, err
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
 
-		findOneOptions := options.FindOne().SetSort(bson.M{"createdAt": -1})
-		filter := bson.M{"_id": bson.M{"$nin": post.GetCommentIds()}}
+// UpdateComment lets the author change the message within config.CommentEditWindow, keeping prior versions
+func UpdateComment(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
 
-		result := models.FindComment(sessCtx, filter, findOneOptions)
-		if result.Comment == nil && result.StatusCode != http.StatusNotFound {
-			return nil, result.Error
-		}
+	commentIdParamValue := c.Param("_id")
+	commentId, err := primitive.ObjectIDFromHex(commentIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid commentId", commentIdParamValue)})
+		return
+	}
 
-		postsCollection := services.GetMongoDBCollection(config.PostsCollection)
-		if result.Comment != nil {
-			update := bson.M{"$push": bson.M{"comments": result.Comment}}
-			_, err := postsCollection.UpdateByID(sessCtx, post.ID, update)
-			if err != nil {
-				return nil, err
-			}
-		}
+	requestBody := &CommentUpdateRequestBody{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"username": 1, "image": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": commentId}))
+	if findCommentResult.Comment == nil {
+		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
+		return
+	}
+
+	comment := findCommentResult.Comment
+	if cliams.ID != comment.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You are not allowed to edit this comment"})
+		return
+	}
+
+	if !comment.IsEditable() {
+		c.JSON(http.StatusForbidden, gin.H{"message": fmt.Sprintf("Comments can only be edited within %v of being posted", config.CommentEditWindow)})
+		return
+	}
+
+	if comment.Message == requestBody.Message {
+		comment.SetUser(findUserResult.User)
+		c.JSON(http.StatusOK, gin.H{"comment": comment})
+		return
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	// An edit can get a comment hidden by the owner's filters but never approves it
+	matchesFilters, err := matchesCommentFilters(ctx, findPostResult.Post.UserID, cliams.ID, requestBody.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	mentions, err := models.FindMentions(ctx, requestBody.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	wasHidden := comment.IsHidden
+	previousMentions := comment.Mentions
+	comment.IsHidden = wasHidden || matchesFilters
+	comment.Mentions = mentions
+	comment.Edit(requestBody.Message)
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
 		update := bson.M{
-			"$inc":  bson.M{"commentsCount": -1},
-			"$pull": bson.M{"comments": bson.M{"_id": commentId}},
+			"$set": bson.M{
+				"edits":    comment.Edits,
+				"editedAt": comment.EditedAt,
+				"isHidden": comment.IsHidden,
+				"mentions": comment.Mentions,
+				"message":  comment.Message,
+			},
 		}
-		_, err = postsCollection.UpdateByID(sessCtx, post.ID, update)
+		_, err := commentsCollection.UpdateByID(sessCtx, comment.ID, update)
 		if err != nil {
 			return nil, err
 		}
 
-		return nil, nil
-	}
+		if comment.IsHidden && !wasHidden {
+			postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+			_, err = postsCollection.UpdateByID(sessCtx, comment.PostID, bson.M{"$pull": bson.M{"pinnedCommentIds": comment.ID}})
+			if err != nil {
+				return nil, err
+			}
+		}
 
+		return nil, models.UpdateCommentInPreview(sessCtx, comment)
+	}
 	_, err = session.WithTransaction(ctx, callback)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	// Only users mentioned for the first time are notified
+	if !comment.IsHidden {
+		mentionedUserIds := models.GetMentionedUserIds(comment.Mentions, previousMentions)
+		err = models.CreateNotifications(ctx, mentionedUserIds, cliams.ID, models.MentionNotification, comment.PostID)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	comment.SetUser(findUserResult.User)
+	c.JSON(http.StatusOK, gin.H{"comment": comment})
 }
 
 func GetComments(c *gin.Context) {
@@ -186,38 +386,44 @@ func GetComments(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	findOneOptions := options.FindOne().SetProjection(bson.M{"commentsCount": 1})
-	findPostResult := models.FindPost(ctx, bson.M{"_id": postId}, findOneOptions)
+	findOneOptions := options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "commentsCount": 1, "pinnedCommentIds": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
 	}
 
-	limitQueryValue := c.Query("limit")
-	limit := config.CommonPaginationLength
-	if limitQueryValue != "" {
-		limit, err = strconv.Atoi(limitQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := 0
-	if skipQueryValue != "" {
-		skip, err = strconv.Atoi(skipQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	pipeline := bson.A{
-		bson.M{"$match": bson.M{"postId": postId}},
-		bson.M{"$sort": bson.M{"createdAt": -1}},
-		bson.M{"$skip": skip},
-		bson.M{"$limit": limit},
+	viewerId := getViewerId(c)
+	hiddenIds, err := models.FindHiddenUserIds(ctx, viewerId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	pinnedCommentIds := bson.A{}
+	for _, pinnedCommentId := range findPostResult.Post.PinnedCommentIDs {
+		pinnedCommentIds = append(pinnedCommentIds, pinnedCommentId)
+	}
+
+	// Hidden comments are only listed for their author
+	visibleFilter := bson.M{
+		"deletedAt": nil,
+		"postId":    postId,
+		"userId":    bson.M{"$nin": hiddenIds},
+		"$or":       bson.A{bson.M{"isHidden": bson.M{"$ne": true}}, bson.M{"userId": viewerId}},
+	}
+	userStages := bson.A{
+		models.AuthorOnlyEditsStage(viewerId),
 		bson.M{
 			"$lookup": bson.M{
 				"from": config.UsersCollection,
@@ -239,6 +445,10 @@ func GetComments(c *gin.Context) {
 		bson.M{"$project": bson.M{"userId": 0}},
 	}
 
+	matchStage := bson.M{"$match": bson.M{"$and": bson.A{visibleFilter, bson.M{"_id": bson.M{"$nin": pinnedCommentIds}}}}}
+	pipeline := append(bson.A{matchStage}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, userStages...)
+
 	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
 	cursor, err := commentsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -246,13 +456,446 @@ func GetComments(c *gin.Context) {
 		return
 	}
 
-	comments := []models.Comment{}
+	comments := []bson.M{}
 	err = cursor.All(ctx, &comments)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	hasNextPage := (limit + skip) < findPostResult.Post.CommentsCount
-	c.JSON(http.StatusOK, gin.H{"comments": comments, "hasNextPage": hasNextPage})
+	page, err := pagination.NewPage(comments, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if params.Cursor != nil || params.Skip > 0 || len(pinnedCommentIds) == 0 {
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	// Pinned comments lead the first page in the order they were pinned
+	matchStage = bson.M{"$match": bson.M{"$and": bson.A{visibleFilter, bson.M{"_id": bson.M{"$in": pinnedCommentIds}}}}}
+	pipeline = append(bson.A{matchStage}, userStages...)
+	cursor, err = commentsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	pinnedComments := []bson.M{}
+	err = cursor.All(ctx, &pinnedComments)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	items := []bson.M{}
+	for _, pinnedCommentId := range pinnedCommentIds {
+		for _, pinnedComment := range pinnedComments {
+			if pinnedComment["_id"] == pinnedCommentId {
+				pinnedComment["isPinned"] = true
+				items = append(items, pinnedComment)
+			}
+		}
+	}
+
+	page.Items = append(items, page.Items...)
+	c.JSON(http.StatusOK, page)
+}
+
+// GetCommentThread returns a comment with its first replies so clients can render a thread in one request
+func GetCommentThread(c *gin.Context) {
+	commentIdParamValue := c.Param("_id")
+	commentId, err := primitive.ObjectIDFromHex(commentIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid commentId", commentIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	viewerId := getViewerId(c)
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": commentId}))
+	if findCommentResult.Comment == nil {
+		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
+		return
+	}
+
+	comment := findCommentResult.Comment
+	if comment.IsHidden && comment.UserID != viewerId {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Comment not found"})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	if !authorizeUserInteraction(ctx, c, comment.UserID) {
+		return
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"username": 1, "image": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": comment.UserID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	hiddenIds, err := models.FindHiddenUserIds(ctx, viewerId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Replies are read oldest first, like a conversation
+	pipeline := bson.A{
+		bson.M{
+			"$match": bson.M{
+				"deletedAt": nil,
+				"replyToId": commentId,
+				"userId":    bson.M{"$nin": hiddenIds},
+				"$or":       bson.A{bson.M{"isHidden": bson.M{"$ne": true}}, bson.M{"userId": viewerId}},
+			},
+		},
+		bson.M{"$sort": bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": config.ThreadRepliesLength + 1},
+		models.AuthorOnlyEditsStage(viewerId),
+	}
+	pipeline = append(pipeline, models.ReplyUserStages()...)
+
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	cursor, err := repliesCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	replies := []bson.M{}
+	err = cursor.All(ctx, &replies)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	hasMoreReplies := len(replies) > config.ThreadRepliesLength
+	if hasMoreReplies {
+		replies = replies[:config.ThreadRepliesLength]
+	}
+
+	if comment.UserID != viewerId {
+		comment.Edits = nil
+	}
+
+	comment.SetUser(findUserResult.User)
+	c.JSON(http.StatusOK, gin.H{"comment": comment, "hasMoreReplies": hasMoreReplies, "replies": replies})
+}
+
+func HideComment(c *gin.Context) {
+	setCommentHidden(c, true)
+}
+
+func UnhideComment(c *gin.Context) {
+	setCommentHidden(c, false)
+}
+
+// setCommentHidden hides a comment from everyone but its author, or shows it again
+func setCommentHidden(c *gin.Context, isHidden bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	comment, post := findModeratedComment(ctx, c)
+	if comment == nil {
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+		_, err := commentsCollection.UpdateByID(sessCtx, comment.ID, bson.M{"$set": bson.M{"isHidden": isHidden}})
+		if err != nil {
+			return nil, err
+		}
+
+		if isHidden && post.IsCommentPinned(comment.ID) {
+			postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+			_, err = postsCollection.UpdateByID(sessCtx, post.ID, bson.M{"$pull": bson.M{"pinnedCommentIds": comment.ID}})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, models.RefreshCommentPreview(sessCtx, post.ID)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func PinComment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	comment, post := findModeratedComment(ctx, c)
+	if comment == nil {
+		return
+	}
+
+	if comment.IsHidden {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Hidden comments cannot be pinned"})
+		return
+	}
+
+	if post.IsCommentPinned(comment.ID) {
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+		return
+	}
+
+	filter := bson.M{
+		"_id": post.ID,
+		fmt.Sprintf("pinnedCommentIds.%v", config.MaxPinnedComments-1): bson.M{"$exists": false},
+	}
+	update := bson.M{"$push": bson.M{"pinnedCommentIds": comment.ID}}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	result, err := postsCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("You can pin up to %v comments", config.MaxPinnedComments)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func UnpinComment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	comment, post := findModeratedComment(ctx, c)
+	if comment == nil {
+		return
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err := postsCollection.UpdateByID(ctx, post.ID, bson.M{"$pull": bson.M{"pinnedCommentIds": comment.ID}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+// findModeratedComment responds with an error and returns nil unless the viewer owns the post of the comment
+func findModeratedComment(ctx context.Context, c *gin.Context) (*models.Comment, *models.Post) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return nil, nil
+	}
+
+	commentIdParamValue := c.Param("_id")
+	commentId, err := primitive.ObjectIDFromHex(commentIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid commentId", commentIdParamValue)})
+		return nil, nil
+	}
+
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": commentId}))
+	if findCommentResult.Comment == nil {
+		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
+		return nil, nil
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"pinnedCommentIds": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": findCommentResult.Comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return nil, nil
+	}
+
+	if cliams.ID != findPostResult.Post.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Only the owner of the post can moderate its comments"})
+		return nil, nil
+	}
+
+	return findCommentResult.Comment, findPostResult.Post
+}
+
+// matchesCommentFilters reports whether a comment or reply by commenterId must be hidden by the post owner's filters
+func matchesCommentFilters(ctx context.Context, ownerId, commenterId interface{}, message string) (bool, error) {
+	if ownerId == commenterId {
+		return false, nil
+	}
+
+	settings, err := models.FindCommentFilterSettings(ctx, ownerId)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.Matches(message), nil
+}
+
+// GetHiddenComments lists the hidden comments of a post for its owner, who can approve them with UnhideComment
+func GetHiddenComments(c *gin.Context) {
+	getHiddenPostChildren(c, config.CommentsCollection)
+}
+
+func getHiddenPostChildren(c *gin.Context, collectionName string) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	postIdQueryValue := c.Query("postId")
+	postId, err := primitive.ObjectIDFromHex(postIdQueryValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdQueryValue)})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if cliams.ID != findPostResult.Post.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Only the owner of the post can moderate its comments"})
+		return
+	}
+
+	matchStage := bson.M{"$match": bson.M{"deletedAt": nil, "postId": postId, "isHidden": true}}
+	pipeline := append(bson.A{matchStage}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline,
+		models.AuthorOnlyEditsStage(cliams.ID),
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"image": 1, "username": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"userId": 0}},
+	)
+
+	collection := services.GetMongoDBCollection(collectionName)
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	items := []bson.M{}
+	err = cursor.All(ctx, &items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(items, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func GetCommentFilterSettings(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"commentFilters": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	settings := findUserResult.User.CommentFilters
+	if settings == nil {
+		settings = models.DefaultCommentFilterSettings()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func UpdateCommentFilterSettings(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	settings := &models.CommentFilterSettings{}
+	messages := helpers.ValidateRequestBody(c, settings)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	// Filters apply to new comments only; comments that were already hidden stay hidden until approved
+	settings.NormalizeFields()
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	result, err := usersCollection.UpdateByID(ctx, cliams.ID, bson.M{"$set": bson.M{"commentFilters": settings}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
 }
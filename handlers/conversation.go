@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ConversationRequestBody struct {
+	Name    string   `json:"name" binding:"max=50"`
+	UserIDs []string `json:"userIds" binding:"required,min=1,dive,object_id"`
+}
+
+func CreateConversation(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	body := &ConversationRequestBody{}
+	messages := helpers.ValidateRequestBody(c, body)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	userIds := []primitive.ObjectID{}
+	seenIds := bson.A{cliams.ID}
+	for _, value := range body.UserIDs {
+		userId, _ := primitive.ObjectIDFromHex(value)
+		if !helpers.Contains(seenIds, userId) {
+			seenIds = append(seenIds, userId)
+			userIds = append(userIds, userId)
+		}
+	}
+
+	if len(userIds) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "You cannot start a conversation with yourself"})
+		return
+	}
+
+	if len(userIds) >= config.MaxConversationMembers {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("A conversation can have at most %v members", config.MaxConversationMembers)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	count, err := usersCollection.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": userIds}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if count != int64(len(userIds)) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	for _, userId := range userIds {
+		if !authorizeUserInteraction(ctx, c, userId) {
+			return
+		}
+	}
+
+	if len(userIds) == 1 {
+		filter := bson.M{"isGroup": false, "memberIds": bson.M{"$all": bson.A{cliams.ID, userIds[0]}}}
+		findConversationResult := models.FindConversation(ctx, filter)
+		if findConversationResult.Conversation != nil {
+			c.JSON(http.StatusOK, gin.H{"conversation": findConversationResult.Conversation})
+			return
+		}
+
+		if findConversationResult.StatusCode != http.StatusNotFound {
+			c.JSON(findConversationResult.StatusCode, findConversationResult.ResponseBody)
+			return
+		}
+	}
+
+	conversation, err := models.NewConversation(ctx, cliams.ID, userIds, strings.TrimSpace(body.Name))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	conversationsCollection := services.GetMongoDBCollection(config.ConversationsCollection)
+	_, err = conversationsCollection.InsertOne(ctx, conversation)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"conversation": conversation})
+}
+
+// GetConversations lists accepted conversations, or message requests when folder is "requests"
+func GetConversations(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	status := models.AcceptedConversationStatus
+	if c.Query("folder") == "requests" {
+		status = models.PendingConversationStatus
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	matchStage := bson.M{"$match": bson.M{"members": bson.M{"$elemMatch": bson.M{"userId": cliams.ID, "status": status}}}}
+	pipeline := append(bson.A{matchStage}, params.Stages("lastMessageAt", "_id")...)
+	pipeline = append(pipeline, bson.M{
+		"$lookup": bson.M{
+			"from": config.UsersCollection,
+			"let":  bson.M{"memberIds": "$memberIds"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$memberIds"}}}},
+				bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
+			},
+			"as": "users",
+		},
+	})
+
+	conversationsCollection := services.GetMongoDBCollection(config.ConversationsCollection)
+	cursor, err := conversationsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	conversations := []bson.M{}
+	err = cursor.All(ctx, &conversations)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(conversations, params, "lastMessageAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func AcceptConversation(c *gin.Context) {
+	updateConversationMember(c, bson.M{"status": models.AcceptedConversationStatus})
+}
+
+func DeclineConversation(c *gin.Context) {
+	updateConversationMember(c, bson.M{"status": models.DeclinedConversationStatus})
+}
+
+// ReadConversation marks every message sent until now as read by the user
+func ReadConversation(c *gin.Context) {
+	updateConversationMember(c, bson.M{"lastReadAt": time.Now()})
+}
+
+func CreateMessage(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	message := &models.Message{}
+	messages := helpers.ValidateRequestBody(c, message)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conversation := findMemberConversation(ctx, c, cliams.ID)
+	if conversation == nil {
+		return
+	}
+
+	err := message.NormalizeFields(conversation.ID, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if message.Text == "" && message.PostID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Text or postId is required"})
+		return
+	}
+
+	for _, memberId := range conversation.MemberIDs {
+		blocked, err := models.IsBlocked(ctx, cliams.ID, memberId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		if blocked && memberId != cliams.ID {
+			c.JSON(http.StatusForbidden, gin.H{"message": "You cannot send messages to this conversation"})
+			return
+		}
+	}
+
+	if message.PostID != nil {
+		findOneOptions := options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+		findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": message.PostID}), findOneOptions)
+		if findPostResult.Post == nil {
+			c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+			return
+		}
+
+		if !authorizePost(ctx, c, findPostResult.Post) {
+			return
+		}
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		messagesCollection := services.GetMongoDBCollection(config.MessagesCollection)
+		_, err := messagesCollection.InsertOne(sessCtx, message)
+		if err != nil {
+			return nil, err
+		}
+
+		// Replying to a message request accepts it
+		filter := bson.M{"_id": conversation.ID, "members.userId": cliams.ID}
+		update := bson.M{
+			"$set": bson.M{
+				"lastMessage":          message.Preview(),
+				"lastMessageAt":        message.CreatedAt,
+				"members.$.lastReadAt": message.CreatedAt,
+				"members.$.status":     models.AcceptedConversationStatus,
+			},
+		}
+		conversationsCollection := services.GetMongoDBCollection(config.ConversationsCollection)
+		_, err = conversationsCollection.UpdateOne(sessCtx, filter, update)
+		return nil, err
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"directMessage": message})
+}
+
+func GetMessages(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conversation := findMemberConversation(ctx, c, cliams.ID)
+	if conversation == nil {
+		return
+	}
+
+	matchStage := bson.M{"$match": bson.M{"conversationId": conversation.ID, "deletedFor": bson.M{"$ne": cliams.ID}}}
+	pipeline := append(bson.A{matchStage}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": bson.M{"deletedFor": 0}})
+
+	messagesCollection := services.GetMongoDBCollection(config.MessagesCollection)
+	cursor, err := messagesCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	messages := []bson.M{}
+	err = cursor.All(ctx, &messages)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(messages, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// DeleteMessage hides the message for the user, or removes its content for every member when for is "everyone"
+func DeleteMessage(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	messageIdParamValue := c.Param("messageId")
+	messageId, err := primitive.ObjectIDFromHex(messageIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid messageId", messageIdParamValue)})
+		return
+	}
+
+	forEveryone := c.Query("for") == "everyone"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conversation := findMemberConversation(ctx, c, cliams.ID)
+	if conversation == nil {
+		return
+	}
+
+	findMessageResult := models.FindMessage(ctx, bson.M{"_id": messageId, "conversationId": conversation.ID})
+	if findMessageResult.Message == nil {
+		c.JSON(findMessageResult.StatusCode, findMessageResult.ResponseBody)
+		return
+	}
+
+	messagesCollection := services.GetMongoDBCollection(config.MessagesCollection)
+	if !forEveryone {
+		_, err = messagesCollection.UpdateByID(ctx, messageId, bson.M{"$addToSet": bson.M{"deletedFor": cliams.ID}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+		return
+	}
+
+	if findMessageResult.Message.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You can only delete your own messages for everyone"})
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		update := bson.M{"$set": bson.M{"isDeleted": true}, "$unset": bson.M{"text": "", "postId": ""}}
+		_, err := messagesCollection.UpdateByID(sessCtx, messageId, update)
+		if err != nil {
+			return nil, err
+		}
+
+		filter := bson.M{"_id": conversation.ID, "lastMessage._id": messageId}
+		update = bson.M{
+			"$set":   bson.M{"lastMessage.isDeleted": true},
+			"$unset": bson.M{"lastMessage.text": "", "lastMessage.postId": ""},
+		}
+		conversationsCollection := services.GetMongoDBCollection(config.ConversationsCollection)
+		_, err = conversationsCollection.UpdateOne(sessCtx, filter, update)
+		return nil, err
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func updateConversationMember(c *gin.Context, fields bson.M) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	conversation := findMemberConversation(ctx, c, cliams.ID)
+	if conversation == nil {
+		return
+	}
+
+	_, err := models.UpdateConversationMember(ctx, conversation.ID, cliams.ID, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+// findMemberConversation responds with an error and returns nil unless the user is a member of the conversation
+func findMemberConversation(ctx context.Context, c *gin.Context, userId primitive.ObjectID) *models.Conversation {
+	conversationIdParamValue := c.Param("_id")
+	conversationId, err := primitive.ObjectIDFromHex(conversationIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid conversationId", conversationIdParamValue)})
+		return nil
+	}
+
+	findConversationResult := models.FindConversation(ctx, bson.M{"_id": conversationId, "memberIds": userId})
+	if findConversationResult.Conversation == nil {
+		c.JSON(findConversationResult.StatusCode, findConversationResult.ResponseBody)
+		return nil
+	}
+
+	return findConversationResult.Conversation
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	eventPingPeriod = 50 * time.Second
+	eventPongWait   = 60 * time.Second
+	eventWriteWait  = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || origin == config.ClientOrigin
+	},
+}
+
+// EventMessage is sent by WebSocket clients to follow the comments and replies of a post
+type EventMessage struct {
+	Action string `json:"action"`
+	PostID string `json:"postId"`
+}
+
+// StreamEvents upgrades to a WebSocket when the client asks for it and falls back to Server-Sent Events,
+// where posts are subscribed to with the postId query parameter
+func StreamEvents(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	unreadCount, err := services.CountUnreadNotifications(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	subscriber := services.SubscribeToEvents(cliams.ID)
+	defer services.UnsubscribeFromEvents(subscriber)
+
+	stream := &eventStream{
+		subscriber: subscriber,
+		initial:    services.Event{Name: services.NotificationsCountEvent, Data: bson.M{"unreadCount": unreadCount}},
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		stream.serveWebSocket(c)
+		return
+	}
+
+	for _, postIdQueryValue := range c.QueryArray("postId") {
+		err = stream.subscribeToPost(ctx, postIdQueryValue)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	stream.serveSSE(c)
+}
+
+type eventStream struct {
+	initial    services.Event
+	subscriber *services.EventSubscriber
+}
+
+func (stream *eventStream) subscribeToPost(ctx context.Context, postIdValue string) error {
+	postId, err := primitive.ObjectIDFromHex(postIdValue)
+	if err != nil {
+		return fmt.Errorf("%v is not a valid postId", postIdValue)
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
+	if findPostResult.Post == nil {
+		return fmt.Errorf("Post not found")
+	}
+
+	_, err = checkPostAccess(ctx, stream.subscriber.UserID, findPostResult.Post)
+	if err != nil {
+		return err
+	}
+
+	stream.subscriber.SubscribeToPost(postId)
+	return nil
+}
+
+// isHidden reports whether the event was caused by a deactivated user or one that has a block with the subscriber. It
+// is checked for every event, so blocks made while the stream is open apply to it. Events are left out when the check
+// fails
+func (stream *eventStream) isHidden(event services.Event) bool {
+	document, ok := event.Data.(bson.M)
+	if !ok || document["userId"] == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	hidden, err := models.IsHidden(ctx, stream.subscriber.UserID, document["userId"])
+	return hidden || err != nil
+}
+
+func (stream *eventStream) serveSSE(c *gin.Context) {
+	ticker := time.NewTicker(eventPingPeriod)
+	defer ticker.Stop()
+
+	c.SSEvent(stream.initial.Name, stream.initial.Data)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			_, err := w.Write([]byte(": ping\n\n"))
+			return err == nil
+		case event, ok := <-stream.subscriber.Events:
+			if !ok {
+				return false
+			}
+
+			if !stream.isHidden(event) {
+				c.SSEvent(event.Name, event.Data)
+			}
+			return true
+		}
+	})
+}
+
+func (stream *eventStream) serveWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	replies := make(chan services.Event, 1)
+	done := make(chan struct{})
+	go stream.readWebSocket(conn, replies, done)
+
+	ticker := time.NewTicker(eventPingPeriod)
+	defer ticker.Stop()
+
+	write := func(event services.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(eventWriteWait))
+		return conn.WriteJSON(event)
+	}
+
+	if write(stream.initial) != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(eventWriteWait))
+			if conn.WriteMessage(websocket.PingMessage, nil) != nil {
+				return
+			}
+		case event := <-replies:
+			if write(event) != nil {
+				return
+			}
+		case event, ok := <-stream.subscriber.Events:
+			if !ok {
+				return
+			}
+
+			if !stream.isHidden(event) && write(event) != nil {
+				return
+			}
+		}
+	}
+}
+
+func (stream *eventStream) readWebSocket(conn *websocket.Conn, replies chan<- services.Event, done chan<- struct{}) {
+	defer close(done)
+
+	conn.SetReadDeadline(time.Now().Add(eventPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(eventPongWait))
+	})
+
+	for {
+		message := EventMessage{}
+		if conn.ReadJSON(&message) != nil {
+			return
+		}
+
+		var err error
+		switch message.Action {
+		case "subscribe":
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			err = stream.subscribeToPost(ctx, message.PostID)
+			cancel()
+		case "unsubscribe":
+			postId, parseErr := primitive.ObjectIDFromHex(message.PostID)
+			if parseErr != nil {
+				err = fmt.Errorf("%v is not a valid postId", message.PostID)
+				break
+			}
+
+			stream.subscriber.UnsubscribeFromPost(postId)
+		default:
+			err = fmt.Errorf("%v is not a supported action", message.Action)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		select {
+		case replies <- services.Event{Name: "error", Data: gin.H{"message": err.Error()}}:
+		default:
+		}
+	}
+}
@@ -2,14 +2,14 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -41,7 +41,7 @@ func FollowUser(c *gin.Context) {
 	}
 
 	filter := bson.M{"_id": bson.M{"$in": bson.A{userToFollowId, cliams.ID}}}
-	findOptions := options.Find().SetProjection(bson.M{"followersCount": 1, "followingCount": 1})
+	findOptions := options.Find().SetProjection(bson.M{"fanOutOnRead": 1, "followersCount": 1, "followingCount": 1, "isPrivate": 1})
 	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
 	cursor, err := usersCollection.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -61,6 +61,39 @@ func FollowUser(c *gin.Context) {
 		return
 	}
 
+	userToFollow := users[0]
+	if userToFollow.ID != userToFollowId {
+		userToFollow = users[1]
+	}
+
+	if !authorizeUserInteraction(ctx, c, userToFollowId) {
+		return
+	}
+
+	if userToFollow.IsPrivate {
+		following, err := models.IsFollowing(ctx, cliams.ID, userToFollowId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		if !following {
+			err = models.CreateFollowRequest(ctx, cliams.ID, userToFollowId)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+				return
+			}
+
+			err = models.CreateNotification(ctx, userToFollowId, cliams.ID, models.FollowRequestNotification, userToFollowId)
+			if err != nil {
+				log.Println(err)
+			}
+
+			c.JSON(http.StatusOK, gin.H{"message": "Follow request sent"})
+			return
+		}
+	}
+
 	session, err := services.GetMongoDBSession()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -68,72 +101,31 @@ func FollowUser(c *gin.Context) {
 	}
 	defer session.EndSession(ctx)
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		// Check if authUser has already followed userToFollow
-		filter := bson.M{"userId": userToFollowId, "followers": cliams.ID}
-		err = userDetailsCollection.FindOne(sessCtx, filter).Err()
-		if !errors.Is(err, mongo.ErrNoDocuments) && err != nil {
-			return nil, err
-		}
-
-		if err == nil {
-			return nil, nil
-		}
+		return models.CreateFollow(sessCtx, cliams.ID, userToFollowId)
+	}
+	created, err := session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-		operations := []mongo.WriteModel{
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"_id": userToFollowId},
-				Update: bson.M{"$inc": bson.M{"followersCount": 1}},
-			},
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"_id": cliams.ID},
-				Update: bson.M{"$inc": bson.M{"followingCount": 1}},
-			},
-		}
-		_, err = usersCollection.BulkWrite(sessCtx, operations)
-		if err != nil {
-			return nil, err
-		}
+	if created != true {
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+		return
+	}
 
-		upsert := true
-		operations = []mongo.WriteModel{
-			&mongo.UpdateOneModel{
-				Filter: bson.M{
-					"userId":         userToFollowId,
-					"followersCount": bson.M{"$lt": config.LargePaginationLength},
-				},
-				Upsert: &upsert,
-				Update: bson.M{
-					"$push":        bson.M{"followers": bson.M{"$each": bson.A{cliams.ID}, "$position": 0}},
-					"$inc":         bson.M{"followersCount": 1},
-					"$setOnInsert": models.NewUserDetails(bson.A{"followers", "followersCount"}),
-				},
-			},
-			&mongo.UpdateOneModel{
-				Filter: bson.M{
-					"followingCount": bson.M{"$lt": config.LargePaginationLength},
-					"userId":         cliams.ID,
-				},
-				Upsert: &upsert,
-				Update: bson.M{
-					"$push":        bson.M{"following": bson.M{"$each": bson.A{userToFollowId}, "$position": 0}},
-					"$inc":         bson.M{"followingCount": 1},
-					"$setOnInsert": models.NewUserDetails(bson.A{"following", "followingCount"}),
-				},
-			},
-		}
-		_, err = userDetailsCollection.BulkWrite(sessCtx, operations)
+	if !models.IsFanOutOnRead(&userToFollow) {
+		err = models.BackfillTimeline(ctx, cliams.ID, userToFollowId)
 		if err != nil {
-			return nil, err
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
 		}
-
-		return nil, nil
 	}
-	_, err = session.WithTransaction(ctx, callback)
+
+	err = models.CreateNotification(ctx, userToFollowId, cliams.ID, models.FollowNotification, userToFollowId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		log.Println(err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Success"})
@@ -182,6 +174,18 @@ func UnfollowUser(c *gin.Context) {
 		return
 	}
 
+	// Unfollowing a private account that has not approved the follow request cancels the request
+	cancelled, err := models.DeleteFollowRequest(ctx, cliams.ID, userToUnfollowId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if cancelled {
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+		return
+	}
+
 	session, err := services.GetMongoDBSession()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -190,40 +194,57 @@ func UnfollowUser(c *gin.Context) {
 	defer session.EndSession(ctx)
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		operations := []mongo.WriteModel{
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"userId": userToUnfollowId, "followers": cliams.ID},
-				Update: bson.M{"$inc": bson.M{"followersCount": -1}, "$pull": bson.M{"followers": cliams.ID}},
-			},
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"userId": cliams.ID, "following": userToUnfollowId},
-				Update: bson.M{"$inc": bson.M{"followingCount": -1}, "$pull": bson.M{"following": cliams.ID}},
-			},
-		}
+		return nil, models.DeleteFollow(sessCtx, cliams.ID, userToUnfollowId)
+	}
 
-		userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-		_, err = userDetailsCollection.BulkWrite(sessCtx, operations)
-		if err != nil {
-			return nil, err
-		}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-		operations = []mongo.WriteModel{
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"_id": userToUnfollowId},
-				Update: bson.M{"$inc": bson.M{"followersCount": -1}},
-			},
-			&mongo.UpdateOneModel{
-				Filter: bson.M{"_id": cliams.ID},
-				Update: bson.M{"$inc": bson.M{"followingCount": -1}},
-			},
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
 
-		_, err = usersCollection.BulkWrite(sessCtx, operations)
-		if err != nil {
-			return nil, err
-		}
+// RemoveFollower is the reverse of UnfollowUser: the user in the path stops following the authenticated user
+func RemoveFollower(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
 
-		return nil, nil
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userIdParamValue := c.Param("_id")
+	followerId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	if cliams.ID == followerId {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "You cannot remove yourself"})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": followerId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.DeleteFollow(sessCtx, followerId, cliams.ID)
 	}
 
 	_, err = session.WithTransaction(ctx, callback)
@@ -235,76 +256,304 @@ func UnfollowUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
 
-func GetUserFollowers(c *gin.Context) {
+func GetCloseFriends(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	projection := bson.M{"username": 1, "image": 1, "name": 1}
+	pipeline := models.UserListPipeline(params, bson.M{"userId": cliams.ID}, "friendId", projection)
+
+	closeFriendsCollection := services.GetMongoDBCollection(config.CloseFriendsCollection)
+	cursor, err := closeFriendsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	users := []bson.M{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(users, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func AddCloseFriend(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
 	userIdParamValue := c.Param("_id")
-	userId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	friendId, err := primitive.ObjectIDFromHex(userIdParamValue)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
 		return
 	}
 
-	findOneOptions := options.FindOne().SetProjection(bson.M{"followersCount": 1})
-	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if cliams.ID == friendId {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "You cannot add yourself to your close friends"})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": friendId}, findOneOptions)
 	if findUserResult.User == nil {
 		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
 		return
 	}
 
-	limitQueryValue := c.Query("limit")
-	limit := config.CommonPaginationLength
-	if limitQueryValue != "" {
-		limit, err = strconv.Atoi(limitQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
+	if !authorizeUserInteraction(ctx, c, friendId) {
+		return
+	}
+
+	err = models.AddCloseFriend(ctx, cliams.ID, friendId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func RemoveCloseFriend(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userIdParamValue := c.Param("_id")
+	friendId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	err = models.RemoveCloseFriend(ctx, cliams.ID, friendId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetFollowRequests(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"userId": cliams.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline,
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"requesterId": "$requesterId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$requesterId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"userId": 0, "requesterId": 0}},
+	)
+
+	followRequestsCollection := services.GetMongoDBCollection(config.FollowRequestsCollection)
+	cursor, err := followRequestsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	requests := []bson.M{}
+	err = cursor.All(ctx, &requests)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(requests, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func ApproveFollowRequest(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	userIdParamValue := c.Param("_id")
+	requesterId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"fanOutOnRead": 1, "followersCount": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		deleted, err := models.DeleteFollowRequest(sessCtx, requesterId, cliams.ID)
+		if err != nil || !deleted {
+			return deleted, err
 		}
+
+		_, err = models.CreateFollow(sessCtx, requesterId, cliams.ID)
+		return deleted, err
+	}
+	approved, err := session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := 0
-	if skipQueryValue != "" {
-		skip, err = strconv.Atoi(skipQueryValue)
+	if approved != true {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Follow request not found"})
+		return
+	}
+
+	if !models.IsFanOutOnRead(findUserResult.User) {
+		err = models.BackfillTimeline(ctx, requesterId, cliams.ID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
 	}
 
-	documentsSkipped := skip / config.LargePaginationLength
-	position := skip % config.LargePaginationLength
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
 
-	matchStage := bson.M{"$match": bson.M{"userId": userId}}
-	sortStage := bson.M{"$sort": bson.M{"createdAt": -1}}
-	skipStage := bson.M{"$skip": documentsSkipped}
-	limitStage := bson.M{"$limit": 1}
-	projectStage := bson.M{
-		"$project": bson.M{
-			"_id":       0,
-			"followers": bson.M{"$slice": bson.A{"$followers", position, limit}},
-		},
+func RejectFollowRequest(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
 	}
-	lookupStage := bson.M{
-		"$lookup": bson.M{
-			"from": config.UsersCollection,
-			"let":  bson.M{"followers": "$followers"},
-			"pipeline": bson.A{
-				bson.M{
-					"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$followers"}}},
-				},
-				bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
-			},
-			"as": "followers",
-		},
+
+	userIdParamValue := c.Param("_id")
+	requesterId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
 	}
-	unwindStage := bson.M{"$unwind": "$followers"}
-	replaceRootStage := bson.M{"$replaceRoot": bson.M{"newRoot": "$followers"}}
-	pipeline := bson.A{matchStage, sortStage, skipStage, limitStage, projectStage, lookupStage, unwindStage, replaceRootStage}
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	cursor, err := userDetailsCollection.Aggregate(ctx, pipeline)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	deleted, err := models.DeleteFollowRequest(ctx, requesterId, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Follow request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetUserFollowers(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userIdParamValue := c.Param("_id")
+	userId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"followersCount": 1, "isPrivate": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	if !authorizeUserContent(ctx, c, findUserResult.User) {
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	params.ExcludedIds, err = models.FindHiddenUserIds(ctx, getViewerId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	projection := bson.M{"username": 1, "image": 1, "name": 1}
+	pipeline := models.UserListPipeline(params, bson.M{"followeeId": userId}, "followerId", projection)
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -317,8 +566,13 @@ func GetUserFollowers(c *gin.Context) {
 		return
 	}
 
-	hasNextPage := (limit + skip) < findUserResult.User.FollowersCount
-	c.JSON(http.StatusOK, gin.H{"users": users, "hasNextPage": hasNextPage})
+	page, err := pagination.NewPage(users, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 func GetUserFollowing(c *gin.Context) {
@@ -332,67 +586,101 @@ func GetUserFollowing(c *gin.Context) {
 		return
 	}
 
-	findOneOptions := options.FindOne().SetProjection(bson.M{"followingCount": 1})
+	findOneOptions := options.FindOne().SetProjection(bson.M{"followingCount": 1, "isPrivate": 1})
 	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
 	if findUserResult.User == nil {
 		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
 		return
 	}
 
-	limitQueryValue := c.Query("limit")
-	limit := config.CommonPaginationLength
-	if limitQueryValue != "" {
-		limit, err = strconv.Atoi(limitQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	if !authorizeUserContent(ctx, c, findUserResult.User) {
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := 0
-	if skipQueryValue != "" {
-		skip, err = strconv.Atoi(skipQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	params.ExcludedIds, err = models.FindHiddenUserIds(ctx, getViewerId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
 
-	documentsSkipped := skip / config.LargePaginationLength
-	position := skip % config.LargePaginationLength
+	projection := bson.M{"username": 1, "image": 1, "name": 1}
+	pipeline := models.UserListPipeline(params, bson.M{"followerId": userId}, "followeeId", projection)
 
-	matchStage := bson.M{"$match": bson.M{"userId": userId}}
-	sortStage := bson.M{"$sort": bson.M{"createdAt": -1}}
-	skipStage := bson.M{"$skip": documentsSkipped}
-	limitStage := bson.M{"$limit": 1}
-	projectStage := bson.M{
-		"$project": bson.M{
-			"_id":       0,
-			"following": bson.M{"$slice": bson.A{"$following", position, limit}},
-		},
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
-	lookupStage := bson.M{
-		"$lookup": bson.M{
-			"from": config.UsersCollection,
-			"let":  bson.M{"following": "$following"},
-			"pipeline": bson.A{
-				bson.M{
-					"$match": bson.M{
-						"$expr": bson.M{"$in": bson.A{"$_id", "$$following"}},
-					},
-				},
-				bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
-			},
-			"as": "following",
-		},
+
+	users := []bson.M{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(users, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetMutualFollowers lists the followers of a user that the viewer also follows
+func GetMutualFollowers(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userIdParamValue := c.Param("_id")
+	userId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"isPrivate": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	if !authorizeUserContent(ctx, c, findUserResult.User) {
+		return
 	}
-	unwindStage := bson.M{"$unwind": "$following"}
-	replaceRootStage := bson.M{"$replaceRoot": bson.M{"newRoot": "$following"}}
-	pipeline := bson.A{matchStage, sortStage, skipStage, limitStage, projectStage, lookupStage, unwindStage, replaceRootStage}
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	cursor, err := userDetailsCollection.Aggregate(ctx, pipeline)
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	stages, err := models.MutualFollowersStages(ctx, cliams.ID, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	projection := bson.M{"username": 1, "image": 1, "name": 1}
+	pipeline := append(stages, models.UserListStages(params, "followerId", projection)...)
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -405,6 +693,56 @@ func GetUserFollowing(c *gin.Context) {
 		return
 	}
 
-	hasNextPage := (limit + skip) < findUserResult.User.FollowingCount
-	c.JSON(http.StatusOK, gin.H{"users": users, "hasNextPage": hasNextPage})
+	page, err := pagination.NewPage(users, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetRelationship still answers when the viewer has blocked the user so that clients can offer to unblock
+func GetRelationship(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	userIdParamValue := c.Param("_id")
+	userId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	blockedByUser, err := models.HasBlocked(ctx, userId, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if blockedByUser {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	relationship, err := models.FindRelationship(ctx, cliams.ID, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"relationship": relationship})
 }
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func CreateHighlight(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	highlight := &models.Highlight{}
+	messages := helpers.ValidateRequestBody(c, highlight)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	stories, err := models.FindArchivedStories(ctx, cliams.ID, highlight.GetStoryIds())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if len(stories) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "None of the stories were found in your archive"})
+		return
+	}
+
+	highlight.NormalizeFields(cliams.ID)
+	highlight.SetStories(stories)
+	highlightsCollection := services.GetMongoDBCollection(config.HighlightsCollection)
+	_, err = highlightsCollection.InsertOne(ctx, highlight)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"highlight": highlight})
+}
+
+func GetUserHighlights(c *gin.Context) {
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"isPrivate": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"username": c.Param("username")}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	if !authorizeUserContent(ctx, c, findUserResult.User) {
+		return
+	}
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"userId": findUserResult.User.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": bson.M{"userId": 0}})
+
+	highlightsCollection := services.GetMongoDBCollection(config.HighlightsCollection)
+	cursor, err := highlightsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	highlights := []bson.M{}
+	err = cursor.All(ctx, &highlights)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(highlights, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// UpdateHighlight replaces the title and the stories of a highlight
+func UpdateHighlight(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	highlightIdParamValue := c.Param("_id")
+	highlightId, err := primitive.ObjectIDFromHex(highlightIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid highlightId", highlightIdParamValue)})
+		return
+	}
+
+	requestBody := &models.Highlight{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findHighlightResult := models.FindHighlight(ctx, bson.M{"_id": highlightId, "userId": cliams.ID})
+	if findHighlightResult.Highlight == nil {
+		c.JSON(findHighlightResult.StatusCode, findHighlightResult.ResponseBody)
+		return
+	}
+
+	stories, err := models.FindArchivedStories(ctx, cliams.ID, requestBody.GetStoryIds())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if len(stories) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "None of the stories were found in your archive"})
+		return
+	}
+
+	highlight := findHighlightResult.Highlight
+	highlight.Title = strings.TrimSpace(requestBody.Title)
+	highlight.SetStories(stories)
+	highlightsCollection := services.GetMongoDBCollection(config.HighlightsCollection)
+	update := bson.M{"$set": bson.M{"stories": highlight.Stories, "title": highlight.Title}}
+	_, err = highlightsCollection.UpdateByID(ctx, highlightId, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"highlight": highlight})
+}
+
+func DeleteHighlight(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	highlightIdParamValue := c.Param("_id")
+	highlightId, err := primitive.ObjectIDFromHex(highlightIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid highlightId", highlightIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	highlightsCollection := services.GetMongoDBCollection(config.HighlightsCollection)
+	result, err := highlightsCollection.DeleteOne(ctx, bson.M{"_id": highlightId, "userId": cliams.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Highlight not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
@@ -2,8 +2,8 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
@@ -18,6 +18,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type PostCommentSettingsRequestBody struct {
+	CommentsAudience *string `json:"commentsAudience" binding:"omitempty,oneof=everyone followers"`
+	CommentsDisabled *bool   `json:"commentsDisabled"`
+}
+
 func CreatePost(c *gin.Context) {
 	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
 	if !ok {
@@ -44,6 +49,17 @@ func CreatePost(c *gin.Context) {
 	user := findUserResult.User
 	post.NormalizeFields(user.ID)
 
+	blockedUsernames, err := models.FindBlockedTaggedUsernames(ctx, user.ID, post.Caption)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if len(blockedUsernames) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("You cannot tag @%v", blockedUsernames[0])})
+		return
+	}
+
 	keys := post.GeneratePresignedURLKeys()
 	urls, err := services.GeneratePresignedURLs(keys)
 	if err != nil {
@@ -71,6 +87,32 @@ func CreatePost(c *gin.Context) {
 		return
 	}
 
+	timelineUserIds := bson.A{user.ID}
+	if !models.IsFanOutOnRead(user) {
+		followerIds, err := models.FindFollowerIds(ctx, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		timelineUserIds = append(timelineUserIds, followerIds...)
+	}
+
+	err = models.AddPostsToTimelines(ctx, timelineUserIds, *post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	taggedUserIds, err := models.FindTaggedUserIds(ctx, post.Caption)
+	if err == nil {
+		err = models.CreateNotifications(ctx, taggedUserIds, user.ID, models.TagNotification, post.ID)
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+
 	post.SetUser(user)
 	c.JSON(http.StatusCreated, gin.H{"post": post, "urls": urls})
 }
@@ -99,8 +141,8 @@ func DeletePost(c *gin.Context) {
 		return
 	}
 
-	findOneOptions = options.FindOne().SetProjection(bson.M{"userId": 1})
-	findPostResult := models.FindPost(ctx, bson.M{"_id": postId}, findOneOptions)
+	findOneOptions = options.FindOne().SetProjection(bson.M{"archivedAt": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
@@ -119,58 +161,171 @@ func DeletePost(c *gin.Context) {
 	defer session.EndSession(ctx)
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
-		_, err := repliesCollection.DeleteMany(sessCtx, bson.M{"postId": postId})
-		if err != nil {
-			return nil, err
-		}
+		return models.SoftDeletePost(sessCtx, findUserResult.User, findPostResult.Post)
+	}
 
-		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
-		_, err = commentsCollection.DeleteMany(sessCtx, bson.M{"postId": postId})
-		if err != nil {
-			return nil, err
-		}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-		postsCollection := services.GetMongoDBCollection(config.PostsCollection)
-		_, err = postsCollection.DeleteOne(sessCtx, bson.M{"_id": postId})
-		if err != nil {
-			return nil, err
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
 
-		user := findUserResult.User
-		update := bson.M{
-			"$pull": bson.M{"posts": bson.M{"_id": postId}},
-			"$inc":  bson.M{"postsCount": -1},
-		}
-		usersCollection := services.GetMongoDBCollection(config.UsersCollection)
-		_, err = usersCollection.UpdateByID(sessCtx, user.ID, update)
-		if err != nil {
-			return nil, err
-		}
+func ArchivePost(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
 
-		if user.PostsCount > config.CommonPaginationLength {
-			recentPost := bson.M{}
-			filter := bson.M{"_id": bson.M{"$nin": user.GetPostIds()}, "userId": user.ID}
-			findOneOptions := options.FindOne().SetProjection(models.PostProjection)
-			findOneOptions.SetSort(bson.M{"createdAt": -1})
-
-			err := postsCollection.FindOne(sessCtx, filter, findOneOptions).Decode(&recentPost)
-			if errors.Is(err, mongo.ErrNoDocuments) {
-				return nil, nil
-			}
-
-			if err != nil {
-				return nil, err
-			}
-
-			update = bson.M{"$push": bson.M{"posts": recentPost}}
-			_, err = usersCollection.UpdateByID(sessCtx, user.ID, update)
-			if err != nil {
-				return nil, err
-			}
-		}
+	postIdParamValue := c.Param("_id")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"posts": 1, "postsCount": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if findPostResult.Post.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You cannot archive this post"})
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return models.ArchivePost(sessCtx, findUserResult.User, postId)
+	}
+
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func UnarchivePost(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	postIdParamValue := c.Param("_id")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"fanOutOnRead": 1, "followersCount": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}))
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if findPostResult.Post.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You cannot unarchive this post"})
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return models.UnarchivePost(sessCtx, findUserResult.User, findPostResult.Post)
+	}
+
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+// RestorePost brings back a post the user deleted within config.DeletedContentRetention
+func RestorePost(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	postIdParamValue := c.Param("_id")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"fanOutOnRead": 1, "followersCount": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	filter := models.RestorableFilter(cliams.ID)
+	filter["_id"] = postId
+	findPostResult := models.FindPost(ctx, filter)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
 
-		return nil, nil
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return models.RestorePost(sessCtx, findUserResult.User, findPostResult.Post)
 	}
 
 	_, err = session.WithTransaction(ctx, callback)
@@ -193,13 +348,16 @@ func GetPost(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	findPostResult := models.FindPost(ctx, bson.M{"_id": postId})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}))
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
 	}
 
 	post := findPostResult.Post
+	if !authorizePost(ctx, c, post) {
+		return
+	}
 
 	findUserResult := models.FindUser(ctx, bson.M{"_id": post.UserID})
 	if findUserResult.User == nil {
@@ -207,6 +365,14 @@ func GetPost(c *gin.Context) {
 		return
 	}
 
+	hiddenIds, err := models.FindHiddenUserIds(ctx, getViewerId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	post.RemoveCommentsByUsers(hiddenIds)
+
 	post.SetUser(findUserResult.User)
 	c.JSON(http.StatusOK, bson.M{"post": post})
 }
@@ -235,22 +401,18 @@ func SavePost(c *gin.Context) {
 		return
 	}
 
-	findPostResult := models.FindPost(ctx, bson.M{"_id": postId})
+	findOneOptions = options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
 	}
 
-	filter := bson.M{"userId": cliams.ID, "savedPostsCount": bson.M{"$lt": config.LargePaginationLength}}
-	update := bson.M{
-		"$push":        bson.M{"savedPosts": bson.M{"$each": bson.A{postId}, "$position": 0}},
-		"$inc":         bson.M{"savedPostsCount": 1},
-		"$setOnInsert": models.NewUserDetails(bson.A{"savedPosts", "savedPostsCount"}),
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
 	}
-	updateOptions := options.Update().SetUpsert(true)
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	_, err = userDetailsCollection.UpdateOne(ctx, filter, update, updateOptions)
+	err = models.SavePost(ctx, cliams.ID, postId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -258,3 +420,102 @@ func SavePost(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
+
+func UnsavePost(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	postIdParamValue := c.Param("_id")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.UnsavePost(sessCtx, cliams.ID, postId)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func UpdatePostCommentSettings(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	postIdParamValue := c.Param("_id")
+	postId, err := primitive.ObjectIDFromHex(postIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid postId", postIdParamValue)})
+		return
+	}
+
+	requestBody := &PostCommentSettingsRequestBody{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"commentsAudience": 1, "commentsDisabled": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": postId}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	post := findPostResult.Post
+	if cliams.ID != post.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You are not allowed to update this post"})
+		return
+	}
+
+	if requestBody.CommentsAudience != nil {
+		post.CommentsAudience = *requestBody.CommentsAudience
+	}
+
+	if requestBody.CommentsDisabled != nil {
+		post.CommentsDisabled = *requestBody.CommentsDisabled
+	}
+
+	update := bson.M{"$set": bson.M{"commentsAudience": post.CommentsAudience, "commentsDisabled": post.CommentsDisabled}}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.UpdateByID(ctx, postId, update)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"commentsAudience": post.CommentsAudience, "commentsDisabled": post.CommentsDisabled})
+}
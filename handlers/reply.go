@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -49,24 +50,86 @@ func CreateReply(c *gin.Context) {
 		return
 	}
 
-	findOneOptions = options.FindOne().SetProjection(bson.M{"_id": 1})
-	findPostResult := models.FindPost(ctx, bson.M{"_id": reply.PostID}, findOneOptions)
+	findOneOptions = options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "commentsAudience": 1, "commentsDisabled": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": reply.PostID}), findOneOptions)
 	if findPostResult.Post == nil {
 		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
 		return
 	}
 
-	findCommentResult := models.FindComment(ctx, bson.M{"_id": reply.ReplyToID})
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	if !authorizeCommenting(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": reply.ReplyToID}))
 	if findCommentResult.Comment == nil {
 		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
 		return
 	}
 
+	if findCommentResult.Comment.IsHidden && findCommentResult.Comment.UserID != cliams.ID {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Comment not found"})
+		return
+	}
+
 	if findCommentResult.Comment.PostID != reply.PostID {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "The comment's postId you are replying to does not match with the reply"})
 		return
 	}
 
+	if !authorizeUserInteraction(ctx, c, findCommentResult.Comment.UserID) {
+		return
+	}
+
+	reply.ReplyToUserID = findCommentResult.Comment.UserID
+	if reply.ParentReplyID != nil {
+		findOneOptions = options.FindOne().SetProjection(bson.M{"isHidden": 1, "replyToId": 1, "userId": 1})
+		findParentReplyResult := models.FindReply(ctx, models.ExcludeDeleted(bson.M{"_id": reply.ParentReplyID}), findOneOptions)
+		if findParentReplyResult.Reply == nil {
+			c.JSON(findParentReplyResult.StatusCode, findParentReplyResult.ResponseBody)
+			return
+		}
+
+		if findParentReplyResult.Reply.IsHidden && findParentReplyResult.Reply.UserID != cliams.ID {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Reply not found"})
+			return
+		}
+
+		if findParentReplyResult.Reply.ReplyToID != reply.ReplyToID {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "The reply you are replying to does not belong to the comment"})
+			return
+		}
+
+		if !authorizeUserInteraction(ctx, c, findParentReplyResult.Reply.UserID) {
+			return
+		}
+
+		reply.ReplyToUserID = findParentReplyResult.Reply.UserID
+	}
+
+	findOneOptions = options.FindOne().SetProjection(bson.M{"username": 1, "image": 1})
+	findReplyToUserResult := models.FindUser(ctx, bson.M{"_id": reply.ReplyToUserID}, findOneOptions)
+	if findReplyToUserResult.User == nil {
+		c.JSON(findReplyToUserResult.StatusCode, findReplyToUserResult.ResponseBody)
+		return
+	}
+
+	reply.IsHidden, err = matchesCommentFilters(ctx, findPostResult.Post.UserID, cliams.ID, reply.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	reply.Mentions, err = models.FindMentions(ctx, reply.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
 	session, err := services.GetMongoDBSession()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -93,7 +156,7 @@ func CreateReply(c *gin.Context) {
 			return nil, err
 		}
 
-		return nil, nil
+		return nil, models.IncrementPreviewRepliesCount(sessCtx, reply.PostID, reply.ReplyToID, 1)
 	}
 	_, err = session.WithTransaction(ctx, callback)
 	if err != nil {
@@ -101,7 +164,21 @@ func CreateReply(c *gin.Context) {
 		return
 	}
 
+	if !reply.IsHidden {
+		err = models.CreateNotification(ctx, reply.ReplyToUserID, cliams.ID, models.ReplyNotification, reply.ReplyToID)
+		if err != nil {
+			log.Println(err)
+		}
+
+		mentionedUserIds := models.GetMentionedUserIds(reply.Mentions, nil)
+		err = models.CreateNotifications(ctx, mentionedUserIds, cliams.ID, models.MentionNotification, reply.PostID)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
 	reply.SetUser(findUserResult.User)
+	reply.SetReplyToUser(findReplyToUserResult.User)
 	c.JSON(http.StatusCreated, gin.H{"reply": reply})
 }
 
@@ -131,7 +208,7 @@ func DeleteReply(c *gin.Context) {
 
 	reply := &models.Reply{}
 	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
-	err = repliesCollection.FindOne(ctx, bson.M{"_id": replyId}).Decode(reply)
+	err = repliesCollection.FindOne(ctx, models.ExcludeDeleted(bson.M{"_id": replyId})).Decode(reply)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Reply not found"})
 		return
@@ -142,7 +219,15 @@ func DeleteReply(c *gin.Context) {
 		return
 	}
 
-	if reply.UserID != cliams.ID {
+	findOneOptions = options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": reply.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	// The post owner moderates every reply on the post
+	if reply.UserID != cliams.ID && findPostResult.Post.UserID != cliams.ID {
 		c.JSON(http.StatusForbidden, gin.H{"message": "You are not allowed to delete this reply"})
 		return
 	}
@@ -155,25 +240,70 @@ func DeleteReply(c *gin.Context) {
 	defer session.EndSession(ctx)
 
 	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
-		_, err := repliesCollection.DeleteOne(sessCtx, bson.M{"_id": reply.ID})
-		if err != nil {
-			return nil, err
-		}
+		return models.SoftDeleteReply(sessCtx, reply, cliams.ID)
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-		commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
-		_, err = commentsCollection.UpdateByID(sessCtx, reply.ReplyToID, bson.M{"$inc": bson.M{"repliesCount": -1}})
-		if err != nil {
-			return nil, err
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
 
-		postsCollection := services.GetMongoDBCollection(config.PostsCollection)
-		_, err = postsCollection.UpdateByID(sessCtx, reply.PostID, bson.M{"$inc": bson.M{"repliesCount": -1}})
-		if err != nil {
-			return nil, err
-		}
+// RestoreReply brings back a reply the user deleted within config.DeletedContentRetention as long as its comment
+// has not been deleted
+func RestoreReply(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
 
-		return nil, nil
+	replyIdParamValue := c.Param("_id")
+	replyId, err := primitive.ObjectIDFromHex(replyIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid replyId", replyIdParamValue)})
+		return
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	filter := models.RestorableFilter(cliams.ID)
+	filter["_id"] = replyId
+	findOneOptions := options.FindOne().SetProjection(bson.M{"postId": 1, "replyToId": 1})
+	findReplyResult := models.FindReply(ctx, filter, findOneOptions)
+	if findReplyResult.Reply == nil {
+		c.JSON(findReplyResult.StatusCode, findReplyResult.ResponseBody)
+		return
+	}
+
+	reply := findReplyResult.Reply
+	findOneOptions = options.FindOne().SetProjection(bson.M{"_id": 1})
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": reply.ReplyToID}), findOneOptions)
+	if findCommentResult.Comment == nil {
+		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
+		return
+	}
+
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": reply.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return models.RestoreReply(sessCtx, reply)
+	}
+
 	_, err = session.WithTransaction(ctx, callback)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -183,6 +313,110 @@ func DeleteReply(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
 
+// UpdateReply lets the author change the message within config.CommentEditWindow, keeping prior versions
+func UpdateReply(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	replyIdParamValue := c.Param("_id")
+	replyId, err := primitive.ObjectIDFromHex(replyIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid replyId", replyIdParamValue)})
+		return
+	}
+
+	requestBody := &CommentUpdateRequestBody{}
+	messages := helpers.ValidateRequestBody(c, requestBody)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"username": 1, "image": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	findReplyResult := models.FindReply(ctx, models.ExcludeDeleted(bson.M{"_id": replyId}))
+	if findReplyResult.Reply == nil {
+		c.JSON(findReplyResult.StatusCode, findReplyResult.ResponseBody)
+		return
+	}
+
+	reply := findReplyResult.Reply
+	if reply.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You are not allowed to edit this reply"})
+		return
+	}
+
+	if !reply.IsEditable() {
+		c.JSON(http.StatusForbidden, gin.H{"message": fmt.Sprintf("Replies can only be edited within %v of being posted", config.CommentEditWindow)})
+		return
+	}
+
+	if reply.Message != requestBody.Message {
+		findOneOptions = options.FindOne().SetProjection(bson.M{"userId": 1})
+		findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": reply.PostID}), findOneOptions)
+		if findPostResult.Post == nil {
+			c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+			return
+		}
+
+		// An edit can get a reply hidden by the owner's filters but never approves it
+		matchesFilters, err := matchesCommentFilters(ctx, findPostResult.Post.UserID, cliams.ID, requestBody.Message)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		mentions, err := models.FindMentions(ctx, requestBody.Message)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		previousMentions := reply.Mentions
+		reply.IsHidden = reply.IsHidden || matchesFilters
+		reply.Mentions = mentions
+		reply.Edit(requestBody.Message)
+		repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+		update := bson.M{
+			"$set": bson.M{
+				"edits":    reply.Edits,
+				"editedAt": reply.EditedAt,
+				"isHidden": reply.IsHidden,
+				"mentions": reply.Mentions,
+				"message":  reply.Message,
+			},
+		}
+		_, err = repliesCollection.UpdateByID(ctx, reply.ID, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		// Only users mentioned for the first time are notified
+		if !reply.IsHidden {
+			mentionedUserIds := models.GetMentionedUserIds(reply.Mentions, previousMentions)
+			err = models.CreateNotifications(ctx, mentionedUserIds, cliams.ID, models.MentionNotification, reply.PostID)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}
+
+	reply.SetUser(findUserResult.User)
+	c.JSON(http.StatusOK, gin.H{"reply": reply})
+}
+
 func GetReplies(c *gin.Context) {
 	replyToIdQueryValue := c.Query("replyToId")
 	replyToId, err := primitive.ObjectIDFromHex(replyToIdQueryValue)
@@ -194,57 +428,58 @@ func GetReplies(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	findCommentResult := models.FindComment(ctx, bson.M{"_id": replyToId})
+	viewerId := getViewerId(c)
+	findCommentResult := models.FindComment(ctx, models.ExcludeDeleted(bson.M{"_id": replyToId}))
 	if findCommentResult.Comment == nil {
 		c.JSON(findCommentResult.StatusCode, findCommentResult.ResponseBody)
 		return
 	}
 
-	limitQueryValue := c.Query("limit")
-	limit := config.CommonPaginationLength
-	if limitQueryValue != "" {
-		limit, err = strconv.Atoi(limitQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	comment := findCommentResult.Comment
+	if comment.IsHidden && comment.UserID != viewerId {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Comment not found"})
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := 0
-	if skipQueryValue != "" {
-		skip, err = strconv.Atoi(skipQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	findOneOptions := options.FindOne().SetProjection(bson.M{"archivedAt": 1, "audience": 1, "userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": comment.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
 	}
 
-	pipeline := bson.A{
-		bson.M{"$match": bson.M{"replyToId": replyToId}},
-		bson.M{"$sort": bson.M{"createdAt": -1}},
-		bson.M{"$skip": skip},
-		bson.M{"$limit": limit},
-		bson.M{
-			"$lookup": bson.M{
-				"from": config.UsersCollection,
-				"let":  bson.M{"userId": "$userId"},
-				"pipeline": bson.A{
-					bson.M{
-						"$match": bson.M{
-							"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}},
-						},
-					},
-					bson.M{
-						"$project": bson.M{"image": 1, "username": 1, "_id": 0},
-					},
-				},
-				"as": "user",
-			},
+	if !authorizePost(ctx, c, findPostResult.Post) {
+		return
+	}
+
+	if !authorizeUserInteraction(ctx, c, comment.UserID) {
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	hiddenIds, err := models.FindHiddenUserIds(ctx, viewerId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Hidden replies are only listed for their author
+	matchStage := bson.M{
+		"$match": bson.M{
+			"deletedAt": nil,
+			"replyToId": replyToId,
+			"userId":    bson.M{"$nin": hiddenIds},
+			"$or":       bson.A{bson.M{"isHidden": bson.M{"$ne": true}}, bson.M{"userId": viewerId}},
 		},
-		bson.M{"$unwind": bson.M{"path": "$user"}},
-		bson.M{"$project": bson.M{"userId": 0}},
 	}
+	pipeline := append(bson.A{matchStage}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, models.AuthorOnlyEditsStage(viewerId))
+	pipeline = append(pipeline, models.ReplyUserStages()...)
 
 	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
 	cursor, err := repliesCollection.Aggregate(ctx, pipeline)
@@ -253,13 +488,77 @@ func GetReplies(c *gin.Context) {
 		return
 	}
 
-	replies := []models.Reply{}
+	replies := []bson.M{}
 	err = cursor.All(ctx, &replies)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	hasNextPage := (limit + skip) < findCommentResult.Comment.RepliesCount
-	c.JSON(http.StatusOK, gin.H{"replies": replies, "hasNextPage": hasNextPage})
+	page, err := pagination.NewPage(replies, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetHiddenReplies lists the hidden replies of a post for its owner, who can approve them with UnhideReply
+func GetHiddenReplies(c *gin.Context) {
+	getHiddenPostChildren(c, config.RepliesCollection)
+}
+
+func HideReply(c *gin.Context) {
+	setReplyHidden(c, true)
+}
+
+func UnhideReply(c *gin.Context) {
+	setReplyHidden(c, false)
+}
+
+// setReplyHidden lets the post owner hide a reply from everyone but its author, or show it again
+func setReplyHidden(c *gin.Context, isHidden bool) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	replyIdParamValue := c.Param("_id")
+	replyId, err := primitive.ObjectIDFromHex(replyIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid replyId", replyIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findReplyResult := models.FindReply(ctx, models.ExcludeDeleted(bson.M{"_id": replyId}), options.FindOne().SetProjection(bson.M{"postId": 1}))
+	if findReplyResult.Reply == nil {
+		c.JSON(findReplyResult.StatusCode, findReplyResult.ResponseBody)
+		return
+	}
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"userId": 1})
+	findPostResult := models.FindPost(ctx, models.ExcludeDeleted(bson.M{"_id": findReplyResult.Reply.PostID}), findOneOptions)
+	if findPostResult.Post == nil {
+		c.JSON(findPostResult.StatusCode, findPostResult.ResponseBody)
+		return
+	}
+
+	if cliams.ID != findPostResult.Post.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Only the owner of the post can moderate its replies"})
+		return
+	}
+
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	_, err = repliesCollection.UpdateByID(ctx, replyId, bson.M{"$set": bson.M{"isHidden": isHidden}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func CreateStory(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	story := &models.Story{}
+	story.NormalizeFields(cliams.ID)
+
+	urls, err := services.GeneratePresignedURLs([]string{story.GeneratePresignedURLKey()})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	story.SetImage(urls[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+		_, err := storiesCollection.InsertOne(sessCtx, story)
+		if err != nil {
+			return nil, err
+		}
+
+		storyArchiveCollection := services.GetMongoDBCollection(config.StoryArchiveCollection)
+		_, err = storyArchiveCollection.InsertOne(sessCtx, story)
+		return nil, err
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"story": story, "url": urls[0]})
+}
+
+// GetStoriesFeed groups the live stories of followed users by author, listing the authors who posted most recently
+// first. hasUnseen tells whether a group has stories the viewer has not seen yet
+func GetStoriesFeed(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	hiddenIds, err := models.FindHiddenUserIds(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Stories are looked up from the follows of the viewer, together with the viewer's own
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"followerId": cliams.ID}},
+		bson.M{"$project": bson.M{"_id": 0, "userId": "$followeeId"}},
+		bson.M{
+			"$unionWith": bson.M{
+				"coll":     config.UsersCollection,
+				"pipeline": bson.A{bson.M{"$match": bson.M{"_id": cliams.ID}}, bson.M{"$project": bson.M{"_id": 0, "userId": "$_id"}}},
+			},
+		},
+		bson.M{"$match": bson.M{"userId": bson.M{"$nin": hiddenIds}}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.StoriesCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$userId", "$$userId"}}, "expiresAt": bson.M{"$gt": time.Now()}}},
+				},
+				"as": "stories",
+			},
+		},
+		bson.M{"$unwind": "$stories"},
+		bson.M{"$replaceRoot": bson.M{"newRoot": "$stories"}},
+		bson.M{"$sort": bson.M{"createdAt": 1}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.StoryViewsCollection,
+				"let":  bson.M{"storyId": "$_id"},
+				"pipeline": bson.A{
+					bson.M{
+						"$match": bson.M{
+							"$expr":    bson.M{"$eq": bson.A{"$storyId", "$$storyId"}},
+							"viewerId": cliams.ID,
+						},
+					},
+					bson.M{"$project": bson.M{"_id": 1}},
+				},
+				"as": "views",
+			},
+		},
+		bson.M{"$addFields": bson.M{"seen": bson.M{"$gt": bson.A{bson.M{"$size": "$views"}, 0}}}},
+		bson.M{
+			"$group": bson.M{
+				"_id":       "$userId",
+				"hasUnseen": bson.M{"$max": bson.M{"$not": bson.A{"$seen"}}},
+				"latestAt":  bson.M{"$max": "$createdAt"},
+				"stories": bson.M{
+					"$push": bson.M{
+						"_id":       "$_id",
+						"createdAt": "$createdAt",
+						"expiresAt": "$expiresAt",
+						"image":     "$image",
+						"seen":      "$seen",
+					},
+				},
+			},
+		},
+	}
+	pipeline = append(pipeline, params.Stages("latestAt", "_id")...)
+	pipeline = append(pipeline,
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$_id"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+	)
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	groups := []bson.M{}
+	err = cursor.All(ctx, &groups)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(groups, params, "latestAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Groups are keyed by the author id only to page through them
+	for _, group := range page.Items {
+		delete(group, "_id")
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func ViewStory(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	story := findLiveStory(ctx, c)
+	if story == nil {
+		return
+	}
+
+	if !authorizeUserContentByID(ctx, c, story.UserID) {
+		return
+	}
+
+	if story.UserID != cliams.ID {
+		err := models.RecordStoryView(ctx, story, cliams.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetStoryViewers(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	story := findLiveStory(ctx, c)
+	if story == nil {
+		return
+	}
+
+	if story.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Only the author can see the viewers of a story"})
+		return
+	}
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"storyId": story.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline,
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"viewerId": "$viewerId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$viewerId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"createdAt": 1, "user": 1}},
+	)
+
+	storyViewsCollection := services.GetMongoDBCollection(config.StoryViewsCollection)
+	cursor, err := storyViewsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	viewers := []bson.M{}
+	err = cursor.All(ctx, &viewers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(viewers, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// DeleteStory removes the story and its views but keeps the archived copy
+func DeleteStory(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	story := findLiveStory(ctx, c)
+	if story == nil {
+		return
+	}
+
+	if story.UserID != cliams.ID {
+		c.JSON(http.StatusForbidden, gin.H{"message": "You can only delete your own stories"})
+		return
+	}
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+		_, err := storiesCollection.DeleteOne(sessCtx, bson.M{"_id": story.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		storyViewsCollection := services.GetMongoDBCollection(config.StoryViewsCollection)
+		_, err = storyViewsCollection.DeleteMany(sessCtx, bson.M{"storyId": story.ID})
+		return nil, err
+	}
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+func GetStoryArchive(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"userId": cliams.ID}}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": bson.M{"userId": 0, "viewersCount": 0}})
+
+	storyArchiveCollection := services.GetMongoDBCollection(config.StoryArchiveCollection)
+	cursor, err := storyArchiveCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	stories := []bson.M{}
+	err = cursor.All(ctx, &stories)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(stories, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// findLiveStory responds with an error and returns nil if the story does not exist or has expired
+func findLiveStory(ctx context.Context, c *gin.Context) *models.Story {
+	storyIdParamValue := c.Param("_id")
+	storyId, err := primitive.ObjectIDFromHex(storyIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid storyId", storyIdParamValue)})
+		return nil
+	}
+
+	// The TTL monitor runs periodically, so expired stories can still be found for a short while
+	findStoryResult := models.FindStory(ctx, bson.M{"_id": storyId, "expiresAt": bson.M{"$gt": time.Now()}})
+	if findStoryResult.Story == nil {
+		c.JSON(findStoryResult.StatusCode, findStoryResult.ResponseBody)
+		return nil
+	}
+
+	return findStoryResult.Story
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type UserPrivacyRequestBody struct {
+	IsPrivate *bool `json:"isPrivate" binding:"required"`
+}
+
+type DeleteUserRequestBody struct {
+	Password string `json:"password" binding:"required"`
+}
+
 func GetUser(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 	defer cancel()
@@ -27,7 +37,46 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"user": result.User})
+	if !authorizeUserInteraction(ctx, c, result.User.ID) {
+		return
+	}
+
+	canView, err := models.CanViewUserContent(ctx, getViewerId(c), result.User)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	viewerId := getViewerId(c)
+	// Private profiles are still visible but their posts are not
+	if !canView {
+		result.User.Posts = []bson.M{}
+	} else {
+		result.User.Posts, err = models.FilterPostsByAudience(ctx, viewerId, result.User.ID, result.User.Posts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	if viewerId.IsZero() || viewerId == result.User.ID {
+		c.JSON(http.StatusOK, gin.H{"user": result.User})
+		return
+	}
+
+	relationship, err := models.FindRelationship(ctx, viewerId, result.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	followedBy, err := models.FindFollowedBy(ctx, viewerId, result.User.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": result.User, "relationship": relationship, "followedBy": followedBy})
 }
 
 func GetUserHomePosts(c *gin.Context) {
@@ -47,112 +96,136 @@ func GetUserHomePosts(c *gin.Context) {
 		return
 	}
 
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
 	user := result.User
 	if user.FollowingCount == 0 && user.PostsCount == 0 {
-		c.JSON(http.StatusOK, gin.H{"posts": bson.A{}})
+		c.JSON(http.StatusOK, &pagination.Page{Items: []bson.M{}})
 		return
 	}
 
-	var err error
-	limitQueryValue := c.Query("limit")
-	limit := uint64(config.CommonPaginationLength)
-	if limitQueryValue != "" {
-		limit, err = strconv.ParseUint(limitQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a positive integer", limitQueryValue)})
-			return
-		}
+	fanOutOnReadUserIds, err := models.FindFanOutOnReadFollowingIds(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := uint64(0)
-	if skipQueryValue != "" {
-		skip, err = strconv.ParseUint(skipQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a positive integer", skipQueryValue)})
-			return
-		}
+	audienceStages, err := models.PostAudienceStages(ctx, cliams.ID, "authorId")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
-	matchStage := bson.M{"$match": bson.M{"userId": cliams.ID, "following": bson.M{"$ne": bson.A{}}}}
-	projectStage := bson.M{"$project": bson.M{"_id": 0, "following": 1, "userId": 1}}
-	groupStage := bson.M{
-		"$group": bson.M{
-			"_id": "$userId",
-			"following": bson.M{
-				"$accumulator": bson.M{
-					"init":           "function() {return []}",
-					"accumulate":     "function(state = [], following) {return [...state, ...following]}",
-					"accumulateArgs": bson.A{"$following"},
-					"merge":          "function(state1, state2) {return [...state1, ...state2]}",
-					"lang":           "js",
+
+	// The posts of authors fanned out on read come from the union below, even those still in the timeline
+	matchStage := bson.M{"$match": bson.M{"userId": cliams.ID, "authorId": bson.M{"$nin": fanOutOnReadUserIds}}}
+	projectStage := bson.M{"$project": bson.M{"_id": 0, "audience": 1, "authorId": 1, "postId": 1, "createdAt": 1}}
+	postsPipeline := bson.A{matchStage, projectStage}
+	if len(fanOutOnReadUserIds) > 0 {
+		unionWithStage := bson.M{
+			"$unionWith": bson.M{
+				"coll": config.PostsCollection,
+				"pipeline": bson.A{
+					bson.M{"$match": models.ExcludeHiddenPosts(bson.M{"userId": bson.M{"$in": fanOutOnReadUserIds}})},
+					bson.M{"$project": bson.M{"_id": 0, "audience": 1, "authorId": "$userId", "postId": "$_id", "createdAt": 1}},
 				},
 			},
-		},
+		}
+		postsPipeline = append(postsPipeline, unionWithStage)
 	}
-	postsLookupStage := bson.M{
-		"$lookup": bson.M{
-			"from": config.PostsCollection,
-			"let":  bson.M{"following": "$following", "userId": "$_id"},
-			"pipeline": bson.A{
-				bson.M{
-					"$match": bson.M{
-						"$expr": bson.M{
-							"$or": bson.A{
-								bson.M{"$in": bson.A{"$userId", "$$following"}},
-								bson.M{"$eq": bson.A{"$userId", "$$userId"}},
-							},
-						},
-					},
-				},
-				bson.M{"$sort": bson.M{"createdAt": -1}},
-				bson.M{"$skip": skip},
-				bson.M{"$limit": limit},
+
+	// The timeline is paged before the audience lookups run, over a window of entries a few times larger than the
+	// page so that most pages are filled even when some posts are left out
+	scanLimit := params.Skip + (params.Limit+1)*config.TimelineScanFactor
+	scanParams := &pagination.Params{Cursor: params.Cursor, Limit: scanLimit - 1}
+	postsPipeline = append(postsPipeline, scanParams.Stages("createdAt", "postId")...)
+
+	itemsPipeline := append(bson.A{}, audienceStages...)
+	if params.Skip > 0 {
+		itemsPipeline = append(itemsPipeline, bson.M{"$skip": params.Skip})
+	}
+	itemsPipeline = append(itemsPipeline,
+		bson.M{"$limit": params.Limit + 1},
+		bson.M{
+			"$lookup": bson.M{
+				"from":         config.PostsCollection,
+				"localField":   "postId",
+				"foreignField": "_id",
+				"as":           "posts",
 			},
-			"as": "posts",
 		},
-	}
-	postsUnwindStage := bson.M{"$unwind": bson.M{"path": "$posts"}}
-	replaceRootStage := bson.M{"$replaceRoot": bson.M{"newRoot": "$posts"}}
-	postsPipeline := bson.A{matchStage, projectStage, groupStage, postsLookupStage, postsUnwindStage, replaceRootStage}
-
-	usersLookupStage := bson.M{
-		"$lookup": bson.M{
-			"from": config.UsersCollection,
-			"let":  bson.M{"userId": "$userId"},
-			"pipeline": bson.A{
-				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
-				bson.M{"$project": bson.M{"username": 1, "image": 1}},
+		bson.M{"$unwind": bson.M{"path": "$posts"}},
+		bson.M{"$replaceRoot": bson.M{"newRoot": "$posts"}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1}},
+				},
+				"as": "user",
 			},
-			"as": "user",
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"userId": 0}},
+	)
+
+	// lastScanned is only set when the window was full, so there may be more entries after it
+	facetStage := bson.M{
+		"$facet": bson.M{
+			"items":       itemsPipeline,
+			"lastScanned": bson.A{bson.M{"$skip": scanLimit - 1}, bson.M{"$project": bson.M{"createdAt": 1, "postId": 1}}},
 		},
 	}
-	usersUnWindStage := bson.M{"$unwind": bson.M{"path": "$user"}}
-	usersProjectStage := bson.M{"$project": bson.M{"userId": 0}}
-	usersPipeline := bson.A{usersLookupStage, usersUnWindStage, usersProjectStage}
+	pipeline := append(postsPipeline, facetStage)
+	timelinesCollection := services.GetMongoDBCollection(config.TimelinesCollection)
+	cursor, err := timelinesCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-	pipeline := append(postsPipeline, usersPipeline...)
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	cursor, err := userDetailsCollection.Aggregate(ctx, pipeline)
+	results := []struct {
+		Items       []bson.M               `bson:"items"`
+		LastScanned []models.TimelineEntry `bson:"lastScanned"`
+	}{}
+	err = cursor.All(ctx, &results)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	posts := []bson.M{}
-	err = cursor.All(ctx, &posts)
+	page, err := pagination.NewPage(results[0].Items, params, "createdAt", "_id")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts})
+	// The audience left out too many posts of the window to fill the page, the next page starts after the window
+	if !page.HasNextPage && len(results[0].LastScanned) > 0 {
+		lastScanned := results[0].LastScanned[0]
+		nextCursor := &pagination.Cursor{CreatedAt: lastScanned.CreatedAt, ID: lastScanned.PostID}
+		page.NextCursor, err = nextCursor.Encode()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		page.HasNextPage = true
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 func GetUserProfilePosts(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	findOneOptions := options.FindOne().SetProjection(bson.M{"postsCount": 1})
+	findOneOptions := options.FindOne().SetProjection(bson.M{"postsCount": 1, "isPrivate": 1})
 	findUserResult := models.FindUser(ctx, bson.M{"username": c.Param("username")}, findOneOptions)
 	if findUserResult.User == nil {
 		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
@@ -160,33 +233,27 @@ func GetUserProfilePosts(c *gin.Context) {
 	}
 
 	user := findUserResult.User
+	if !authorizeUserContent(ctx, c, user) {
+		return
+	}
 
-	var err error
-	limitQueryValue := c.Query("limit")
-	limit := uint64(config.CommonPaginationLength)
-	if limitQueryValue != "" {
-		limit, err = strconv.ParseUint(limitQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := uint64(0)
-	if skipQueryValue != "" {
-		skip, err = strconv.ParseUint(skipQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	audienceFilter, err := models.AuthorPostAudienceFilter(ctx, getViewerId(c), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
 
-	findOptions := options.Find().SetProjection(models.PostProjection).SetSort(bson.M{"createdAt": -1})
-	findOptions = findOptions.SetSkip(int64(skip)).SetLimit(int64(limit))
+	pipeline := append(bson.A{bson.M{"$match": models.ExcludeHiddenPosts(audienceFilter)}}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": models.PostProjection})
 
 	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
-	cursor, err := postsCollection.Find(ctx, bson.M{"userId": user.ID}, findOptions)
+	cursor, err := postsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -199,8 +266,13 @@ func GetUserProfilePosts(c *gin.Context) {
 		return
 	}
 
-	hasNextPage := int(limit+skip) < user.PostsCount
-	c.JSON(http.StatusOK, gin.H{"posts": posts, "hasNextPage": hasNextPage})
+	page, err := pagination.NewPage(posts, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 func GetUserSavedPosts(c *gin.Context) {
@@ -219,58 +291,80 @@ func GetUserSavedPosts(c *gin.Context) {
 		return
 	}
 
-	var err error
-	limitQueryValue := c.Query("limit")
-	limit := uint64(config.CommonPaginationLength)
-	if limitQueryValue != "" {
-		limit, err = strconv.ParseUint(limitQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := uint64(0)
-	if skipQueryValue != "" {
-		skip, err = strconv.ParseUint(skipQueryValue, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	audienceStages, err := models.PostAudienceStages(ctx, cliams.ID, "userId")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
 
-	documentsSkipped := skip / config.LargePaginationLength
-	position := skip % config.LargePaginationLength
+	visibleAuthorStages, err := models.VisibleAuthorStages(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	params.ItemFilter = models.ExcludeHiddenPosts(bson.M{})
+	params.ItemStages = append(audienceStages, visibleAuthorStages...)
 
 	matchStage := bson.M{"$match": bson.M{"userId": cliams.ID}}
-	sortStage := bson.M{"$sort": bson.M{"createdAt": -1}}
-	skipStage := bson.M{"$skip": documentsSkipped}
-	limitStage := bson.M{"$limit": 1}
-	projectStage := bson.M{
-		"$project": bson.M{
-			"_id":        0,
-			"savedPosts": bson.M{"$slice": bson.A{"$savedPosts", position, limit}},
-		},
+	pipeline := append(bson.A{matchStage}, params.BucketStages("savedPosts", config.PostsCollection, models.PostProjection)...)
+
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	cursor, err := userDetailsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
-	lookupStage := bson.M{
-		"$lookup": bson.M{
-			"from": config.PostsCollection,
-			"let":  bson.M{"savedPosts": "$savedPosts"},
-			"pipeline": bson.A{
-				bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$savedPosts"}}}},
-				bson.M{"$project": models.PostProjection},
-			},
-			"as": "savedPosts",
-		},
+
+	posts := []bson.M{}
+	err = cursor.All(ctx, &posts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
-	unwindStage := bson.M{"$unwind": "$savedPosts"}
-	replaceRootStage := bson.M{"$replaceRoot": bson.M{"newRoot": "$savedPosts"}}
 
-	pipeline := bson.A{matchStage, sortStage, skipStage, limitStage, projectStage, lookupStage, unwindStage, replaceRootStage}
+	page, err := pagination.NewPage(posts, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	cursor, err := userDetailsCollection.Aggregate(ctx, pipeline)
+	c.JSON(http.StatusOK, page)
+}
+
+func GetUserArchivedPosts(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	matchStage := bson.M{"$match": bson.M{"userId": cliams.ID, "archivedAt": bson.M{"$ne": nil}}}
+	projection := bson.M{"archivedAt": 1}
+	for key, value := range models.PostProjection {
+		projection[key] = value
+	}
+
+	pipeline := append(bson.A{matchStage}, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": projection})
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	cursor, err := postsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -283,7 +377,79 @@ func GetUserSavedPosts(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts})
+	page, err := pagination.NewPage(posts, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetRecentlyDeleted lists what the user deleted and can still restore. The type query selects posts (the default),
+// comments or replies
+func GetRecentlyDeleted(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	projections := map[string]bson.M{
+		models.CommentsDeletedContentType: {"edits": 0},
+		models.PostsDeletedContentType:    {"deletedAt": 1},
+		models.RepliesDeletedContentType:  {"edits": 0},
+	}
+	for key, value := range models.PostProjection {
+		projections[models.PostsDeletedContentType][key] = value
+	}
+
+	collectionNames := map[string]string{
+		models.CommentsDeletedContentType: config.CommentsCollection,
+		models.PostsDeletedContentType:    config.PostsCollection,
+		models.RepliesDeletedContentType:  config.RepliesCollection,
+	}
+
+	contentType := c.DefaultQuery("type", models.PostsDeletedContentType)
+	projection, ok := projections[contentType]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid type", contentType)})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	pipeline := append(bson.A{bson.M{"$match": models.RestorableFilter(cliams.ID)}}, params.Stages("deletedAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": projection})
+
+	collection := services.GetMongoDBCollection(collectionNames[contentType])
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	items := []bson.M{}
+	err = cursor.All(ctx, &items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page, err := pagination.NewPage(items, params, "deletedAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 func GetUserSimilarPosts(c *gin.Context) {
@@ -300,6 +466,7 @@ func GetUserSimilarPosts(c *gin.Context) {
 	matchStage := bson.M{"$match": bson.M{"username": c.Param("username")}}
 	firstProjectStage := bson.M{
 		"$project": bson.M{
+			"isPrivate": 1,
 			"posts": bson.M{
 				"$filter": bson.M{
 					"input": "$posts",
@@ -309,14 +476,7 @@ func GetUserSimilarPosts(c *gin.Context) {
 			},
 		},
 	}
-	secondProjectStage := bson.M{
-		"$project": bson.M{
-			"posts": bson.M{
-				"$slice": bson.A{"$posts", 9},
-			},
-		},
-	}
-	pipeline := bson.A{matchStage, firstProjectStage, secondProjectStage}
+	pipeline := bson.A{matchStage, firstProjectStage}
 
 	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
 	cursor, err := usersCollection.Aggregate(ctx, pipeline)
@@ -337,51 +497,64 @@ func GetUserSimilarPosts(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": users[0].Posts})
+	if !authorizeUserContent(ctx, c, &users[0]) {
+		return
+	}
+
+	posts, err := models.FilterPostsByAudience(ctx, getViewerId(c), users[0].ID, users[0].Posts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if len(posts) > config.SimilarPostsLength {
+		posts = posts[:config.SimilarPostsLength]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
 }
 
 func GetUserTaggedPosts(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	findUserResult := models.FindUser(ctx, bson.M{"username": c.Param("username")}, options.FindOne().SetProjection(bson.M{"_id": 1}))
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1, "isPrivate": 1, "username": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"username": c.Param("username")}, findOneOptions)
 	if findUserResult.User == nil {
 		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
 		return
 	}
 
-	var err error
-	limitQueryValue := c.Query("limit")
-	limit := config.CommonPaginationLength
-	if limitQueryValue != "" {
-		limit, err = strconv.Atoi(limitQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", limitQueryValue)})
-			return
-		}
+	if !authorizeUserContent(ctx, c, findUserResult.User) {
+		return
 	}
 
-	skipQueryValue := c.Query("skip")
-	skip := 0
-	if skipQueryValue != "" {
-		skip, err = strconv.Atoi(skipQueryValue)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid integer", skipQueryValue)})
-			return
-		}
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	pipeline := bson.A{
-		bson.M{
-			"$match": bson.M{
-				"caption": bson.M{"$regex": fmt.Sprintf("@%v", c.Param("username")), "$options": "m"},
-			},
-		},
-		bson.M{"$project": models.PostProjection},
-		bson.M{"$sort": bson.M{"createdAt": -1}},
-		bson.M{"$skip": skip},
-		bson.M{"$limit": limit},
+	matchStage := bson.M{
+		"$match": models.ExcludeHiddenPosts(bson.M{
+			"caption": bson.M{"$regex": models.TaggedUsernameRegex(findUserResult.User.Username)},
+		}),
+	}
+	visibilityStages, err := models.VisibleAuthorStages(ctx, getViewerId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	audienceStages, err := models.PostAudienceStages(ctx, getViewerId(c), "userId")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
 	}
+
+	pipeline := append(append(bson.A{matchStage}, audienceStages...), visibilityStages...)
+	pipeline = append(pipeline, params.Stages("createdAt", "_id")...)
+	pipeline = append(pipeline, bson.M{"$project": models.PostProjection})
 	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
 	cursor, err := postsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -396,5 +569,271 @@ func GetUserTaggedPosts(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts})
+	page, err := pagination.NewPage(posts, params, "createdAt", "_id")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func UpdateUserPrivacy(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	body := &UserPrivacyRequestBody{}
+	messages := helpers.ValidateRequestBody(c, body)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+		result, err := usersCollection.UpdateByID(sessCtx, cliams.ID, bson.M{"$set": bson.M{"isPrivate": *body.IsPrivate}})
+		if err != nil || result.MatchedCount == 0 {
+			return result, err
+		}
+
+		// Pending requests are approved once the account becomes public
+		if !*body.IsPrivate {
+			return result, models.ApproveFollowRequests(sessCtx, cliams.ID)
+		}
+
+		return result, nil
+	}
+	result, err := session.WithTransaction(ctx, callback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if updateResult, ok := result.(*mongo.UpdateResult); !ok || updateResult.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"isPrivate": *body.IsPrivate})
+}
+
+// DeactivateUser hides the user's profile and content until the user logs in again
+func DeactivateUser(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	_, err := models.DeactivateUser(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.SetCookie(config.AccessTokenCookieName, "", -1, "/", "", config.IsProduction, true)
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
+}
+
+// DeleteUser hides the account right away and leaves its permanent deletion to the account deletion job
+func DeleteUser(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	body := &DeleteUserRequestBody{}
+	messages := helpers.ValidateRequestBody(c, body)
+	if messages != nil {
+		c.JSON(http.StatusBadRequest, messages)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"deactivatedAt": 1, "deletionRequestedAt": 1, "password": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	user := findUserResult.User
+	matches, err := user.ComparePassword(body.Password)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !matches {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid Password"})
+		return
+	}
+
+	if user.DeletionRequestedAt == nil {
+		err = models.RequestAccountDeletion(ctx, user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	c.SetCookie(config.AccessTokenCookieName, "", -1, "/", "", config.IsProduction, true)
+	c.JSON(http.StatusAccepted, gin.H{"message": "Your account will be deleted"})
+}
+
+// RequestDataExport queues an export of the user's data for the data export job, which notifies the user with a
+// download link once the export is ready
+func RequestDataExport(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": cliams.ID}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	inProgress, err := models.IsDataExportInProgress(ctx, cliams.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if inProgress {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "An export of your data is already being prepared"})
+		return
+	}
+
+	export := &models.DataExport{}
+	export.NormalizeFields(cliams.ID)
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	_, err = dataExportsCollection.InsertOne(ctx, export)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "An export of your data is already being prepared"})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"export": export})
+}
+
+// GetUserSuggestions is paged with skip because suggestions are ranked rather than ordered by time
+func GetUserSuggestions(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	params, err := pagination.ParseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	pipeline, err := models.SuggestionsPipeline(ctx, cliams.ID, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	if pipeline == nil {
+		c.JSON(http.StatusOK, &pagination.Page{Items: []bson.M{}})
+		return
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	users := []bson.M{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	page := &pagination.Page{Items: users}
+	if int64(len(users)) > params.Limit {
+		page.Items = users[:params.Limit]
+		page.HasNextPage = true
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func DismissSuggestion(c *gin.Context) {
+	cliams, ok := c.MustGet("user").(*services.AccessTokenClaim)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Could not parse decoded token"})
+		return
+	}
+
+	userIdParamValue := c.Param("_id")
+	userId, err := primitive.ObjectIDFromHex(userIdParamValue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%v is not a valid userId", userIdParamValue)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	findOneOptions := options.FindOne().SetProjection(bson.M{"_id": 1})
+	findUserResult := models.FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if findUserResult.User == nil {
+		c.JSON(findUserResult.StatusCode, findUserResult.ResponseBody)
+		return
+	}
+
+	err = models.DismissSuggestion(ctx, cliams.ID, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getViewerId returns primitive.NilObjectID for anonymous requests
+func getViewerId(c *gin.Context) primitive.ObjectID {
+	value, exists := c.Get("user")
+	if !exists {
+		return primitive.NilObjectID
+	}
+
+	cliams, ok := value.(*services.AccessTokenClaim)
+	if !ok {
+		return primitive.NilObjectID
+	}
+
+	return cliams.ID
+}
+
+// authorizeUserContent responds with an error and returns false if the viewer cannot see the owner's content
+func authorizeUserContent(ctx context.Context, c *gin.Context, owner *models.User) bool {
+	if !authorizeUserInteraction(ctx, c, owner.ID) {
+		return false
+	}
+
+	canView, err := models.CanViewUserContent(ctx, getViewerId(c), owner)
+	return respondToVisibility(c, canView, err)
+}
+
+func authorizeUserContentByID(ctx context.Context, c *gin.Context, ownerId interface{}) bool {
+	if !authorizeUserInteraction(ctx, c, ownerId) {
+		return false
+	}
+
+	canView, err := models.CanViewUserContentByID(ctx, getViewerId(c), ownerId)
+	return respondToVisibility(c, canView, err)
+}
+
+// authorizeUserInteraction hides deactivated users and users that have a block with the viewer as if they did not exist
+func authorizeUserInteraction(ctx context.Context, c *gin.Context, userId interface{}) bool {
+	hidden, err := models.IsHidden(ctx, getViewerId(c), userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+
+	if hidden {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return false
+	}
+
+	return true
+}
+
+// authorizePost hides posts the viewer is not in the audience of, or that are archived, as if they did not exist
+func authorizePost(ctx context.Context, c *gin.Context, post *models.Post) bool {
+	statusCode, err := checkPostAccess(ctx, getViewerId(c), post)
+	if err != nil {
+		c.JSON(statusCode, gin.H{"message": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// checkPostAccess returns the status code and error authorizePost responds with. Event streams call it directly,
+// since they have no request to respond to once they are upgraded
+func checkPostAccess(ctx context.Context, viewerId primitive.ObjectID, post *models.Post) (int, error) {
+	hidden, err := models.IsHidden(ctx, viewerId, post.UserID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if hidden {
+		return http.StatusNotFound, errors.New("User not found")
+	}
+
+	canView, err := models.CanViewUserContentByID(ctx, viewerId, post.UserID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if !canView {
+		return http.StatusForbidden, errors.New("This account is private")
+	}
+
+	inAudience, err := models.IsInPostAudience(ctx, viewerId, post.UserID, post.Audience)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if !inAudience || post.IsHiddenFrom(viewerId) {
+		return http.StatusNotFound, errors.New("Post not found")
+	}
+
+	return http.StatusOK, nil
+}
+
+func respondToVisibility(c *gin.Context, canView bool, err error) bool {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+
+	if !canView {
+		c.JSON(http.StatusForbidden, gin.H{"message": "This account is private"})
+		return false
+	}
+
+	return true
+}
+
+// authorizeCommenting responds with an error and returns false if the post owner does not accept comments from the viewer
+func authorizeCommenting(ctx context.Context, c *gin.Context, post *models.Post) bool {
+	viewerId := getViewerId(c)
+	if viewerId == post.UserID {
+		return true
+	}
+
+	if post.CommentsDisabled {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Comments are turned off for this post"})
+		return false
+	}
+
+	if post.CommentsAudience != models.FollowersCommentsAudience {
+		return true
+	}
+
+	isFollowing, err := models.IsFollowing(ctx, viewerId, post.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return false
+	}
+
+	if !isFollowing {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Only followers can comment on this post"})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TimelineBackfillReport struct {
+	Follows   int64 `json:"follows"`
+	Unflagged int64 `json:"unflagged"`
+	Users     int64 `json:"users"`
+}
+
+// BackfillTimelines adds the latest posts of every user, and of the users they follow unless those are fanned out
+// on read, to their timeline. It fills the timelines once when upgrading from a version without them, and can
+// safely be run again.
+//
+// Users that dropped back under config.TimelineFanOutLimit are fanned out on write again before their followers'
+// timelines are filled, so none of their posts is missing from home feeds afterwards
+func BackfillTimelines(ctx context.Context) (*TimelineBackfillReport, error) {
+	report := &TimelineBackfillReport{}
+	filter := bson.M{"fanOutOnRead": true, "followersCount": bson.M{"$lte": config.TimelineFanOutLimit}}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	result, err := usersCollection.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"fanOutOnRead": ""}})
+	if err != nil {
+		return report, err
+	}
+
+	report.Unflagged = result.ModifiedCount
+	fanOutOnReadIds, err := usersCollection.Distinct(ctx, "_id", models.FanOutOnReadFilter())
+	if err != nil {
+		return report, err
+	}
+
+	cursor, err := usersCollection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return report, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		user := models.User{}
+		err = cursor.Decode(&user)
+		if err != nil {
+			return report, err
+		}
+
+		err = models.BackfillTimeline(ctx, user.ID, user.ID)
+		if err != nil {
+			return report, err
+		}
+
+		report.Users++
+	}
+
+	if err = cursor.Err(); err != nil {
+		return report, err
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	followsCursor, err := followsCollection.Find(ctx, bson.M{"followeeId": bson.M{"$nin": fanOutOnReadIds}})
+	if err != nil {
+		return report, err
+	}
+	defer followsCursor.Close(ctx)
+
+	for followsCursor.Next(ctx) {
+		follow := models.Follow{}
+		err = followsCursor.Decode(&follow)
+		if err != nil {
+			return report, err
+		}
+
+		err = models.BackfillTimeline(ctx, follow.FollowerID, follow.FolloweeID)
+		if err != nil {
+			return report, err
+		}
+
+		report.Follows++
+	}
+
+	return report, followsCursor.Err()
+}
@@ -0,0 +1,321 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AccountDeletionReport struct {
+	Accounts int64 `json:"accounts"`
+	DeletedContentPurgeReport
+}
+
+func (report *AccountDeletionReport) IsEmpty() bool {
+	return report.Accounts == 0
+}
+
+// DeleteRequestedAccounts permanently removes the accounts whose owners asked for it, along with their content and
+// their place in other users' followers, following, saved posts and counters. Every step only touches what is left
+// of the account and the user document goes last, so a run that fails or is interrupted is resumed by the next one.
+// Messages are kept for the other members of the conversations
+func DeleteRequestedAccounts(ctx context.Context) (*AccountDeletionReport, error) {
+	report := &AccountDeletionReport{}
+	filter := bson.M{"deletionRequestedAt": bson.M{"$ne": nil}}
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1}).SetSort(bson.M{"deletionRequestedAt": 1})
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	cursor, err := usersCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return report, err
+	}
+
+	users := []models.User{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		return report, err
+	}
+
+	for _, user := range users {
+		err = deleteAccount(ctx, user.ID, report)
+		if err != nil {
+			return report, err
+		}
+
+		report.Accounts++
+	}
+
+	return report, nil
+}
+
+func deleteAccount(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	for {
+		purged, err := purgePosts(ctx, bson.M{"userId": userId}, &report.DeletedContentPurgeReport)
+		if err != nil {
+			return err
+		}
+
+		if purged < config.MaxPurgeBatchSize {
+			break
+		}
+	}
+
+	steps := []func(context.Context, primitive.ObjectID, *AccountDeletionReport) error{
+		deleteAccountComments,
+		deleteAccountReplies,
+		deleteAccountFollows,
+		deleteAccountStories,
+		deleteAccountNotifications,
+		deleteAccountDataExports,
+		deleteAccountDocuments,
+	}
+	for _, step := range steps {
+		err := step(ctx, userId, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.DeleteOne(ctx, bson.M{"_id": userId})
+	return err
+}
+
+// withTransaction runs callback in a transaction of its own
+func withTransaction(ctx context.Context, callback func(sessCtx mongo.SessionContext) error) error {
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, callback(sessCtx)
+	})
+	return err
+}
+
+// deleteAccountComments removes the comments the user left on other users' posts one by one so that the counts of
+// every post stay right
+func deleteAccountComments(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	projection := bson.M{"_id": 1, "deletedAt": 1, "postId": 1}
+	findOptions := options.Find().SetProjection(projection).SetLimit(config.MaxAccountDeletionBatchSize)
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	for {
+		cursor, err := commentsCollection.Find(ctx, bson.M{"userId": userId}, findOptions)
+		if err != nil {
+			return err
+		}
+
+		comments := []models.Comment{}
+		err = cursor.All(ctx, &comments)
+		if err != nil {
+			return err
+		}
+
+		for index := range comments {
+			comment := &comments[index]
+			err = withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+				return models.PurgeComment(sessCtx, comment)
+			})
+			if err != nil {
+				return err
+			}
+
+			report.Comments++
+		}
+
+		if len(comments) < config.MaxAccountDeletionBatchSize {
+			return nil
+		}
+	}
+}
+
+func deleteAccountReplies(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	projection := bson.M{"_id": 1, "deletedAt": 1, "postId": 1, "replyToId": 1}
+	findOptions := options.Find().SetProjection(projection).SetLimit(config.MaxAccountDeletionBatchSize)
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	for {
+		cursor, err := repliesCollection.Find(ctx, bson.M{"userId": userId}, findOptions)
+		if err != nil {
+			return err
+		}
+
+		replies := []models.Reply{}
+		err = cursor.All(ctx, &replies)
+		if err != nil {
+			return err
+		}
+
+		for index := range replies {
+			reply := &replies[index]
+			err = withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+				return models.PurgeReply(sessCtx, reply)
+			})
+			if err != nil {
+				return err
+			}
+
+			report.Replies++
+		}
+
+		if len(replies) < config.MaxAccountDeletionBatchSize {
+			return nil
+		}
+	}
+}
+
+// deleteAccountFollows removes the user's follows in both directions along with the counts they add to other users
+func deleteAccountFollows(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	filter := bson.M{"$or": bson.A{bson.M{"followerId": userId}, bson.M{"followeeId": userId}}}
+	findOptions := options.Find().SetLimit(config.MaxAccountDeletionBatchSize)
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	for {
+		cursor, err := followsCollection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return err
+		}
+
+		follows := []models.Follow{}
+		err = cursor.All(ctx, &follows)
+		if err != nil {
+			return err
+		}
+
+		for _, follow := range follows {
+			err = withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+				return models.DeleteFollow(sessCtx, follow.FollowerID, follow.FolloweeID)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(follows) < config.MaxAccountDeletionBatchSize {
+			return nil
+		}
+	}
+}
+
+// deleteAccountStories removes the user's stories, including the archived ones and their images, and the views the
+// user left on other users' stories
+func deleteAccountStories(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	storyArchiveCollection := services.GetMongoDBCollection(config.StoryArchiveCollection)
+	storyIds, err := storyArchiveCollection.Distinct(ctx, "_id", bson.M{"userId": userId})
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	for _, storyId := range storyIds {
+		if id, ok := storyId.(primitive.ObjectID); ok {
+			keys = append(keys, (&models.Story{ID: id}).GeneratePresignedURLKey())
+		}
+	}
+
+	err = services.DeleteObjects(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	report.Objects += int64(len(keys))
+	storyViewsCollection := services.GetMongoDBCollection(config.StoryViewsCollection)
+	viewedStoryIds, err := storyViewsCollection.Distinct(ctx, "storyId", bson.M{"viewerId": userId})
+	if err != nil {
+		return err
+	}
+
+	err = withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+		filter := bson.M{"_id": bson.M{"$in": viewedStoryIds}}
+		_, err := storiesCollection.UpdateMany(sessCtx, filter, bson.M{"$inc": bson.M{"viewersCount": -1}})
+		if err != nil {
+			return err
+		}
+
+		_, err = storyViewsCollection.DeleteMany(sessCtx, bson.M{"viewerId": userId})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	collections := map[string]bson.M{
+		config.HighlightsCollection:   {"userId": userId},
+		config.StoriesCollection:      {"userId": userId},
+		config.StoryArchiveCollection: {"userId": userId},
+		config.StoryViewsCollection:   {"ownerId": userId},
+	}
+	return deleteFromCollections(ctx, collections)
+}
+
+// deleteAccountNotifications removes the notifications of the user and those of other users the user was the only
+// actor of, and takes the user out of the actors of the rest
+func deleteAccountNotifications(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	filter := bson.M{"$or": bson.A{bson.M{"userId": userId}, bson.M{"actorIds": bson.A{userId}}}}
+	notificationsCollection := services.GetMongoDBCollection(config.NotificationsCollection)
+	_, err := notificationsCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	_, err = notificationsCollection.UpdateMany(ctx, bson.M{"actorIds": userId}, bson.M{"$pull": bson.M{"actorIds": userId}})
+	return err
+}
+
+// deleteAccountDataExports removes the user's data exports along with their files
+func deleteAccountDataExports(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	keys, err := dataExportsCollection.Distinct(ctx, "key", bson.M{"userId": userId, "key": bson.M{"$ne": nil}})
+	if err != nil {
+		return err
+	}
+
+	objectKeys := []string{}
+	for _, key := range keys {
+		if value, ok := key.(string); ok {
+			objectKeys = append(objectKeys, value)
+		}
+	}
+
+	err = services.DeleteObjects(ctx, objectKeys)
+	if err != nil {
+		return err
+	}
+
+	report.Objects += int64(len(objectKeys))
+	_, err = dataExportsCollection.DeleteMany(ctx, bson.M{"userId": userId})
+	return err
+}
+
+// deleteAccountDocuments removes the rest of the documents that belong to or point at the user
+func deleteAccountDocuments(ctx context.Context, userId primitive.ObjectID, report *AccountDeletionReport) error {
+	collections := map[string]bson.M{
+		config.BlocksCollection:               {"$or": bson.A{bson.M{"blockerId": userId}, bson.M{"userId": userId}}},
+		config.CloseFriendsCollection:         {"$or": bson.A{bson.M{"userId": userId}, bson.M{"friendId": userId}}},
+		config.DismissedSuggestionsCollection: {"$or": bson.A{bson.M{"userId": userId}, bson.M{"dismissedUserId": userId}}},
+		config.FollowRequestsCollection:       {"$or": bson.A{bson.M{"requesterId": userId}, bson.M{"userId": userId}}},
+		config.SavedCollectionPostsCollection: {"userId": userId},
+		config.SavedCollectionsCollection:     {"userId": userId},
+		config.TimelinesCollection:            {"$or": bson.A{bson.M{"userId": userId}, bson.M{"authorId": userId}}},
+		config.UserDetailsCollection:          {"userId": userId},
+	}
+	return deleteFromCollections(ctx, collections)
+}
+
+func deleteFromCollections(ctx context.Context, collections map[string]bson.M) error {
+	for name, filter := range collections {
+		collection := services.GetMongoDBCollection(name)
+		_, err := collection.DeleteMany(ctx, filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DataExportReport struct {
+	Completed int64 `json:"completed"`
+	Expired   int64 `json:"expired"`
+	Failed    int64 `json:"failed"`
+}
+
+func (report *DataExportReport) IsEmpty() bool {
+	return report.Completed == 0 && report.Expired == 0 && report.Failed == 0
+}
+
+// ProcessDataExports removes the ZIPs whose download links expired, then assembles the pending data exports into
+// ZIPs of JSON files, stores them and notifies their users. Each export is claimed before it is assembled, so
+// concurrent runs on several API instances never build the same one. An export that could not be assembled or stored
+// is marked as failed so its user can request another one
+func ProcessDataExports(ctx context.Context) (*DataExportReport, error) {
+	report := &DataExportReport{}
+	err := expireDataExports(ctx, report)
+	if err != nil {
+		return report, err
+	}
+
+	for index := 0; index < config.MaxDataExportBatchSize; index++ {
+		export, err := models.ClaimDataExport(ctx)
+		if err != nil || export == nil {
+			return report, err
+		}
+
+		url, err := storeDataExport(ctx, export)
+		if err != nil {
+			log.Printf("Data export %v failed: %v", export.ID.Hex(), err)
+			report.Failed++
+			err = models.FailDataExport(ctx, export)
+			if err != nil {
+				return report, err
+			}
+
+			continue
+		}
+
+		err = models.CompleteDataExport(ctx, export, url)
+		if err != nil {
+			return report, err
+		}
+
+		report.Completed++
+	}
+
+	return report, nil
+}
+
+// expireDataExports deletes the ZIPs of the exports completed longer ago than their download links last
+func expireDataExports(ctx context.Context, report *DataExportReport) error {
+	filter := bson.M{
+		"status":      models.CompletedDataExportStatus,
+		"completedAt": bson.M{"$lt": time.Now().Add(-config.DataExportLinkTTL)},
+	}
+	findOptions := options.Find().SetProjection(bson.M{"key": 1}).SetLimit(config.MaxPurgeBatchSize)
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	cursor, err := dataExportsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+
+	exports := []models.DataExport{}
+	err = cursor.All(ctx, &exports)
+	if err != nil || len(exports) == 0 {
+		return err
+	}
+
+	ids := bson.A{}
+	keys := []string{}
+	for _, export := range exports {
+		ids = append(ids, export.ID)
+		if export.Key != "" {
+			keys = append(keys, export.Key)
+		}
+	}
+
+	// Objects go first so that a failure leaves the exports to be expired on the next run
+	err = services.DeleteObjects(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"status": models.ExpiredDataExportStatus}, "$unset": bson.M{"key": ""}}
+	result, err := dataExportsCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return err
+	}
+
+	report.Expired += result.ModifiedCount
+	return nil
+}
+
+func storeDataExport(ctx context.Context, export *models.DataExport) (string, error) {
+	files, err := collectUserData(ctx, export.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for _, name := range dataExportFileNames {
+		file, err := writer.Create(name)
+		if err != nil {
+			return "", err
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(files[name])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	key := export.GenerateKey()
+	err = services.UploadObject(ctx, key, buffer.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	return services.GeneratePresignedDownloadURL(ctx, key, config.DataExportLinkTTL)
+}
+
+var dataExportFileNames = []string{
+	"profile.json",
+	"posts.json",
+	"comments.json",
+	"replies.json",
+	"followers.json",
+	"following.json",
+	"saved_posts.json",
+}
+
+// collectUserData returns the content of every file of the export by its name
+func collectUserData(ctx context.Context, userId primitive.ObjectID) (map[string]interface{}, error) {
+	profile := bson.M{}
+	findOneOptions := options.FindOne().SetProjection(bson.M{"password": 0, "posts": 0})
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	err := usersCollection.FindOne(ctx, bson.M{"_id": userId}, findOneOptions).Decode(&profile)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := bson.M{"userId": userId}
+	newestFirst := options.Find().SetSort(bson.M{"createdAt": -1})
+	// The comments embedded in posts are previews of other users' comments
+	postsOptions := options.Find().SetSort(bson.M{"createdAt": -1}).SetProjection(bson.M{"comments": 0})
+	posts, err := findDocuments(ctx, config.PostsCollection, byUser, postsOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := findDocuments(ctx, config.CommentsCollection, byUser, newestFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := findDocuments(ctx, config.RepliesCollection, byUser, newestFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := findFollowUsers(ctx, bson.M{"followeeId": userId}, "followerId")
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := findFollowUsers(ctx, bson.M{"followerId": userId}, "followeeId")
+	if err != nil {
+		return nil, err
+	}
+
+	savedPosts, err := findSavedPosts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"profile.json":     profile,
+		"posts.json":       posts,
+		"comments.json":    comments,
+		"replies.json":     replies,
+		"followers.json":   followers,
+		"following.json":   following,
+		"saved_posts.json": savedPosts,
+	}, nil
+}
+
+func findDocuments(ctx context.Context, name string, filter bson.M, findOptions *options.FindOptions) ([]bson.M, error) {
+	collection := services.GetMongoDBCollection(name)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := []bson.M{}
+	err = cursor.All(ctx, &documents)
+	return documents, err
+}
+
+// findFollowUsers lists the users referenced by userField of the follows matching filter, newest first
+func findFollowUsers(ctx context.Context, filter bson.M, userField string) ([]bson.M, error) {
+	pipeline := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.M{"createdAt": -1}},
+		bson.M{
+			"$lookup": bson.M{
+				"from":         config.UsersCollection,
+				"localField":   userField,
+				"foreignField": "_id",
+				"as":           "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{"$project": bson.M{"_id": "$user._id", "followedAt": "$createdAt", "name": "$user.name", "username": "$user.username"}},
+	}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []bson.M{}
+	err = cursor.All(ctx, &users)
+	return users, err
+}
+
+// findSavedPosts returns the ids of the saved posts, newest first, and the collections with the ids of their posts
+func findSavedPosts(ctx context.Context, userId primitive.ObjectID) (bson.M, error) {
+	byUser := bson.M{"userId": userId}
+	newestFirst := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	buckets, err := findDocuments(ctx, config.UserDetailsCollection, byUser, newestFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	postIds := bson.A{}
+	for _, bucket := range buckets {
+		if ids, ok := bucket["savedPosts"].(bson.A); ok {
+			postIds = append(postIds, ids...)
+		}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": byUser},
+		bson.M{"$sort": bson.M{"createdAt": -1}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.SavedCollectionPostsCollection,
+				"let":  bson.M{"collectionId": "$_id"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$collectionId", "$$collectionId"}}}},
+					bson.M{"$sort": bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+				},
+				"as": "buckets",
+			},
+		},
+		bson.M{
+			"$project": bson.M{
+				"createdAt": 1,
+				"name":      1,
+				"posts": bson.M{
+					"$reduce": bson.M{
+						"input":        "$buckets.posts",
+						"initialValue": bson.A{},
+						"in":           bson.M{"$concatArrays": bson.A{"$$value", "$$this"}},
+					},
+				},
+			},
+		},
+	}
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	cursor, err := savedCollectionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []bson.M{}
+	err = cursor.All(ctx, &collections)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"collections": collections, "posts": postIds}, nil
+}
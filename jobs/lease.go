@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// leaseHolder identifies this instance in the leases it takes
+var leaseHolder = primitive.NewObjectID()
+
+// acquireLease takes or renews the lease on name for duration. Leases are stored under the name of their job. It returns
+// false if another instance holds an unexpired lease, so the instance that ran the job last keeps running it until it
+// stops renewing its lease
+func acquireLease(ctx context.Context, name string, duration time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{bson.M{"expiresAt": bson.M{"$lte": now}}, bson.M{"holderId": leaseHolder}},
+	}
+	update := bson.M{"$set": bson.M{"expiresAt": now.Add(duration), "holderId": leaseHolder}}
+	collection := services.GetMongoDBCollection(config.JobLeasesCollection)
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// The upsert conflicts with the lease of the other instance
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FollowMigrationReport struct {
+	Buckets int64 `json:"buckets"`
+	Follows int64 `json:"follows"`
+}
+
+// MigrateFollows copies the followers and following arrays of the user_details buckets into the follows collection
+// and removes them from the buckets. It can safely be run again if it is interrupted.
+//
+// The buckets do not record when each follow happened, so follows are dated after the bucket they were stored in,
+// keeping the newest first order of the arrays
+func MigrateFollows(ctx context.Context) (*FollowMigrationReport, error) {
+	report := &FollowMigrationReport{}
+	filter := bson.M{"$or": bson.A{bson.M{"followers": bson.M{"$exists": true}}, bson.M{"following": bson.M{"$exists": true}}}}
+	findOptions := options.Find().SetProjection(bson.M{"createdAt": 1, "followers": 1, "following": 1, "userId": 1})
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	cursor, err := userDetailsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return report, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		bucket := struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			CreatedAt time.Time          `bson:"createdAt"`
+			Followers []interface{}      `bson:"followers"`
+			Following []interface{}      `bson:"following"`
+			UserID    interface{}        `bson:"userId"`
+		}{}
+		err = cursor.Decode(&bucket)
+		if err != nil {
+			return report, err
+		}
+
+		operations := []mongo.WriteModel{}
+		for index, followerId := range bucket.Followers {
+			createdAt := bucket.CreatedAt.Add(time.Duration(len(bucket.Followers)-index) * time.Millisecond)
+			operations = append(operations, newFollowUpsert(followerId, bucket.UserID, createdAt))
+		}
+
+		for index, followeeId := range bucket.Following {
+			createdAt := bucket.CreatedAt.Add(time.Duration(len(bucket.Following)-index) * time.Millisecond)
+			operations = append(operations, newFollowUpsert(bucket.UserID, followeeId, createdAt))
+		}
+
+		if len(operations) > 0 {
+			followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+			result, err := followsCollection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+			if err != nil {
+				return report, err
+			}
+
+			report.Follows += result.UpsertedCount
+		}
+
+		update := bson.M{"$unset": bson.M{"followers": "", "followersCount": "", "following": "", "followingCount": ""}}
+		_, err = userDetailsCollection.UpdateByID(ctx, bucket.ID, update)
+		if err != nil {
+			return report, err
+		}
+
+		report.Buckets++
+	}
+
+	if err = cursor.Err(); err != nil {
+		return report, err
+	}
+
+	// Buckets that only held friendships are left empty
+	filter = bson.M{"$or": bson.A{bson.M{"savedPosts": bson.M{"$exists": false}}, bson.M{"savedPosts": bson.A{}}}}
+	_, err = userDetailsCollection.DeleteMany(ctx, filter)
+	return report, err
+}
+
+func newFollowUpsert(followerId, followeeId interface{}, createdAt time.Time) mongo.WriteModel {
+	upsert := true
+	return &mongo.UpdateOneModel{
+		Filter: bson.M{"followerId": followerId, "followeeId": followeeId},
+		Update: bson.M{"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "createdAt": createdAt}},
+		Upsert: &upsert,
+	}
+}
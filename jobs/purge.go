@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeletedContentPurgeReport struct {
+	Comments int64 `json:"comments"`
+	Objects  int64 `json:"objects"`
+	Posts    int64 `json:"posts"`
+	Replies  int64 `json:"replies"`
+}
+
+// PurgeDeletedContent permanently removes the posts, comments and replies that were soft deleted more than
+// config.DeletedContentRetention ago. Purging a post also removes its images from storage and every comment and
+// reply on it; purging a comment removes its replies
+func PurgeDeletedContent(ctx context.Context) (*DeletedContentPurgeReport, error) {
+	report := &DeletedContentPurgeReport{}
+	expired := bson.M{"deletedAt": bson.M{"$lte": time.Now().Add(-config.DeletedContentRetention)}}
+
+	for {
+		purged, err := purgePosts(ctx, expired, report)
+		if err != nil {
+			return report, err
+		}
+
+		if purged < config.MaxPurgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		purged, err := purgeComments(ctx, expired, report)
+		if err != nil {
+			return report, err
+		}
+
+		if purged < config.MaxPurgeBatchSize {
+			break
+		}
+	}
+
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.DeleteMany(ctx, expired)
+	if err != nil {
+		return report, err
+	}
+
+	report.Replies += result.DeletedCount
+	return report, nil
+}
+
+// purgePosts permanently removes one batch of the posts matching filter, along with everything that refers to them,
+// and returns its size
+func purgePosts(ctx context.Context, filter bson.M, report *DeletedContentPurgeReport) (int, error) {
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1, "images": 1}).SetLimit(config.MaxPurgeBatchSize)
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	cursor, err := postsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	posts := []models.Post{}
+	err = cursor.All(ctx, &posts)
+	if err != nil || len(posts) == 0 {
+		return 0, err
+	}
+
+	postIds := bson.A{}
+	keys := []string{}
+	for _, post := range posts {
+		postIds = append(postIds, post.ID)
+		for index := range post.Images {
+			keys = append(keys, post.ID.Hex()+"/"+strconv.Itoa(index))
+		}
+	}
+
+	// Objects go first so that a failure leaves the posts in place to be purged on the next run
+	err = services.DeleteObjects(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	report.Objects += int64(len(keys))
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, models.RemoveSavedPosts(sessCtx, postIds)
+	}
+
+	_, err = session.WithTransaction(ctx, callback)
+	if err != nil {
+		return 0, err
+	}
+
+	filter = bson.M{"postId": bson.M{"$in": postIds}}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	report.Replies += result.DeletedCount
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	result, err = commentsCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	report.Comments += result.DeletedCount
+	timelinesCollection := services.GetMongoDBCollection(config.TimelinesCollection)
+	_, err = timelinesCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err = postsCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": postIds}})
+	if err != nil {
+		return 0, err
+	}
+
+	report.Posts += result.DeletedCount
+	return len(posts), nil
+}
+
+// purgeComments removes one batch of expired comments and returns its size
+func purgeComments(ctx context.Context, expired bson.M, report *DeletedContentPurgeReport) (int, error) {
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(config.MaxPurgeBatchSize)
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	cursor, err := commentsCollection.Find(ctx, expired, findOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	comments := []models.Comment{}
+	err = cursor.All(ctx, &comments)
+	if err != nil || len(comments) == 0 {
+		return 0, err
+	}
+
+	commentIds := bson.A{}
+	for _, comment := range comments {
+		commentIds = append(commentIds, comment.ID)
+	}
+
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.DeleteMany(ctx, bson.M{"replyToId": bson.M{"$in": commentIds}})
+	if err != nil {
+		return 0, err
+	}
+
+	report.Replies += result.DeletedCount
+	result, err = commentsCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": commentIds}})
+	if err != nil {
+		return 0, err
+	}
+
+	report.Comments += result.DeletedCount
+	return len(comments), nil
+}
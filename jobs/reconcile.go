@@ -0,0 +1,207 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
+
+// A denormalized counter stored on Collection.Field that is recomputed by grouping the documents of
+// SourceCollection matching SourceFilter on SourceKey and summing SourceValue
+type counter struct {
+	Collection       string
+	Field            string
+	SourceCollection string
+	SourceFilter     bson.M
+	SourceKey        string
+	SourceValue      interface{}
+}
+
+var counters = []counter{
+	{config.UsersCollection, "followersCount", config.FollowsCollection, bson.M{}, "followeeId", 1},
+	{config.UsersCollection, "followingCount", config.FollowsCollection, bson.M{}, "followerId", 1},
+	{config.UsersCollection, "postsCount", config.PostsCollection, models.ExcludeHiddenPosts(bson.M{}), "userId", 1},
+	{config.PostsCollection, "commentsCount", config.CommentsCollection, models.ExcludeDeleted(bson.M{}), "postId", 1},
+	{config.PostsCollection, "repliesCount", config.RepliesCollection, models.ExcludeDeleted(bson.M{}), "postId", 1},
+	{config.CommentsCollection, "repliesCount", config.RepliesCollection, models.ExcludeDeleted(bson.M{}), "replyToId", 1},
+	{config.StoriesCollection, "viewersCount", config.StoryViewsCollection, bson.M{}, "storyId", 1},
+}
+
+type CounterDiscrepancy struct {
+	Actual     int64       `json:"actual"`
+	Collection string      `json:"collection"`
+	DocumentID interface{} `json:"documentId"`
+	Field      string      `json:"field"`
+	Stored     int64       `json:"stored"`
+}
+
+type CounterReconciliationReport struct {
+	Checked       int64                `json:"checked"`
+	Discrepancies []CounterDiscrepancy `json:"discrepancies"`
+	Fixed         int64                `json:"fixed"`
+}
+
+// ReconcileCounters recomputes every denormalized counter from its source collection.
+// When fix is true, every drifted document is recounted and overwritten in its own transaction, because the
+// source may have changed since the whole collection was counted
+func ReconcileCounters(ctx context.Context, fix bool) (*CounterReconciliationReport, error) {
+	report := &CounterReconciliationReport{Discrepancies: []CounterDiscrepancy{}}
+	for _, counter := range counters {
+		err := reconcileCounter(ctx, counter, fix, report)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func reconcileCounter(ctx context.Context, counter counter, fix bool, report *CounterReconciliationReport) error {
+	actualCounts, err := countSourceDocuments(ctx, counter)
+	if err != nil {
+		return err
+	}
+
+	collection := services.GetMongoDBCollection(counter.Collection)
+	findOptions := options.Find().SetProjection(bson.M{counter.Field: 1})
+	cursor, err := collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		document := bson.M{}
+		err = cursor.Decode(&document)
+		if err != nil {
+			return err
+		}
+
+		report.Checked++
+		stored := toInt64(document[counter.Field])
+		actual := actualCounts[document["_id"]]
+		if stored == actual {
+			continue
+		}
+
+		report.Discrepancies = append(report.Discrepancies, CounterDiscrepancy{
+			Actual:     actual,
+			Collection: counter.Collection,
+			DocumentID: document["_id"],
+			Field:      counter.Field,
+			Stored:     stored,
+		})
+		if !fix {
+			continue
+		}
+
+		fixed, err := fixCounter(ctx, counter, document["_id"])
+		if err != nil {
+			return err
+		}
+
+		if fixed {
+			report.Fixed++
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (counter counter) sourcePipeline(filter bson.M) bson.A {
+	return bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{"_id": "$" + counter.SourceKey, "count": bson.M{"$sum": counter.SourceValue}}},
+	}
+}
+
+func countSourceDocuments(ctx context.Context, counter counter) (map[interface{}]int64, error) {
+	collection := services.GetMongoDBCollection(counter.SourceCollection)
+	cursor, err := collection.Aggregate(ctx, counter.sourcePipeline(counter.SourceFilter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := map[interface{}]int64{}
+	for cursor.Next(ctx) {
+		group := bson.M{}
+		err = cursor.Decode(&group)
+		if err != nil {
+			return nil, err
+		}
+
+		if group["_id"] != nil {
+			counts[group["_id"]] = toInt64(group["count"])
+		}
+	}
+
+	return counts, cursor.Err()
+}
+
+// fixCounter recounts the source of a single document and stores the count from the same snapshot. A follow,
+// comment or reply written meanwhile updates the counter too, so the transaction conflicts and is retried instead
+// of overwriting it with a stale count
+func fixCounter(ctx context.Context, counter counter, documentId interface{}) (bool, error) {
+	session, err := services.GetMongoDBSession()
+	if err != nil {
+		return false, err
+	}
+	defer session.EndSession(ctx)
+
+	callback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"$and": bson.A{counter.SourceFilter, bson.M{counter.SourceKey: documentId}}}
+		sourceCollection := services.GetMongoDBCollection(counter.SourceCollection)
+		cursor, err := sourceCollection.Aggregate(sessCtx, counter.sourcePipeline(filter))
+		if err != nil {
+			return false, err
+		}
+
+		groups := []bson.M{}
+		err = cursor.All(sessCtx, &groups)
+		if err != nil {
+			return false, err
+		}
+
+		actual := int64(0)
+		if len(groups) > 0 {
+			actual = toInt64(groups[0]["count"])
+		}
+
+		update := bson.M{"$set": bson.M{counter.Field: actual}}
+		collection := services.GetMongoDBCollection(counter.Collection)
+		result, err := collection.UpdateOne(sessCtx, bson.M{"_id": documentId, counter.Field: bson.M{"$ne": actual}}, update)
+		if err != nil {
+			return false, err
+		}
+
+		return result.ModifiedCount == 1, nil
+	}
+
+	transactionOptions := options.Transaction().SetReadConcern(readconcern.Snapshot())
+	fixed, err := session.WithTransaction(ctx, callback, transactionOptions)
+	if err != nil {
+		return false, err
+	}
+
+	return fixed.(bool), nil
+}
+
+func toInt64(value interface{}) int64 {
+	switch number := value.(type) {
+	case int32:
+		return int64(number)
+	case int64:
+		return number
+	case float64:
+		return int64(number)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Scheduled runs get at least this long, even when they are scheduled more often
+const minScheduledRunTimeout = 10 * time.Minute
+
+// A Job runs once and returns a report of what it did
+type Job func(ctx context.Context) (interface{}, error)
+
+// Reports of runs that had nothing to do implement emptyReport, so they are not logged
+type emptyReport interface {
+	IsEmpty() bool
+}
+
+// Schedule runs job every interval until ctx is done and logs its reports under name. Every instance schedules the
+// job, but a run only starts on the instance holding the lease on name, which lasts as long as the run may take
+func Schedule(ctx context.Context, interval time.Duration, name string, job Job) {
+	timeout := interval
+	if timeout < minScheduledRunTimeout {
+		timeout = minScheduledRunTimeout
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := acquireLease(ctx, name, timeout)
+				if err != nil {
+					log.Printf("%v: %v", name, err)
+					continue
+				}
+
+				if !acquired {
+					continue
+				}
+
+				runCtx, cancel := context.WithTimeout(ctx, timeout)
+				report, err := job(runCtx)
+				cancel()
+				if err != nil {
+					log.Printf("%v: %v", name, err)
+					continue
+				}
+
+				if empty, ok := report.(emptyReport); ok && empty.IsEmpty() {
+					continue
+				}
+
+				output, err := json.Marshal(report)
+				if err != nil {
+					log.Printf("%v: %v", name, err)
+					continue
+				}
+
+				log.Printf("%v: %s", name, output)
+			}
+		}
+	}()
+}
@@ -1,15 +1,94 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 )
 
+// The jobs are adapted to jobs.Job so they can be run once or scheduled
+var (
+	accountDeletion     jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.DeleteRequestedAccounts(ctx) }
+	counterFixes        jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.ReconcileCounters(ctx, true) }
+	counterReport       jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.ReconcileCounters(ctx, false) }
+	dataExports         jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.ProcessDataExports(ctx) }
+	deletedContentPurge jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.PurgeDeletedContent(ctx) }
+	followMigration     jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.MigrateFollows(ctx) }
+	timelineBackfill    jobs.Job = func(ctx context.Context) (interface{}, error) { return jobs.BackfillTimelines(ctx) }
+)
+
 func main() {
+	reconcileCounters := flag.Bool("reconcile-counters", false, "report drifted denormalized counters and exit")
+	fix := flag.Bool("fix", false, "with -reconcile-counters, overwrite drifted counters with the recomputed values")
+	migrateFollows := flag.Bool("migrate-follows", false, "move the friendships stored in user_details into the follows collection and exit")
+	backfillTimelines := flag.Bool("backfill-timelines", false, "add the latest posts of followed users to every home timeline and exit")
+	purgeDeleted := flag.Bool("purge-deleted", false, "permanently remove the content deleted longer ago than it can be restored and exit")
+	deleteAccounts := flag.Bool("delete-accounts", false, "permanently delete the accounts whose owners asked for it and exit")
+	exportData := flag.Bool("export-data", false, "assemble the requested data exports and exit")
+	flag.Parse()
+
 	services.CreateMongoDBConnection()
+	switch {
+	case *migrateFollows:
+		runJob(followMigration)
+		return
+	case *backfillTimelines:
+		runJob(timelineBackfill)
+		return
+	case *reconcileCounters && *fix:
+		runJob(counterFixes)
+		return
+	case *reconcileCounters:
+		runJob(counterReport)
+		return
+	case *purgeDeleted:
+		runJob(deletedContentPurge)
+		return
+	case *deleteAccounts:
+		runJob(accountDeletion)
+		return
+	case *exportData:
+		runJob(dataExports)
+		return
+	}
+
+	scheduledJobs := []struct {
+		interval time.Duration
+		name     string
+		job      jobs.Job
+	}{
+		{config.CounterReconciliationInterval, "Counter reconciliation", counterFixes},
+		{config.DeletedContentPurgeInterval, "Deleted content purge", deletedContentPurge},
+		{config.AccountDeletionInterval, "Account deletion", accountDeletion},
+		{config.DataExportInterval, "Data exports", dataExports},
+	}
+	for _, scheduled := range scheduledJobs {
+		if scheduled.interval > 0 {
+			jobs.Schedule(context.Background(), scheduled.interval, scheduled.name, scheduled.job)
+		}
+	}
+
 	router := routes.SetupRouter()
 	err := router.Run(":" + config.Port)
 	helpers.ExitIfError(err)
 }
+
+func runJob(job jobs.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	report, err := job(ctx)
+	helpers.ExitIfError(err)
+
+	output, err := json.MarshalIndent(report, "", "  ")
+	helpers.ExitIfError(err)
+	fmt.Println(string(output))
+}
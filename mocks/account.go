@@ -0,0 +1,117 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AccountMockResult struct {
+	CollectionID primitive.ObjectID
+	FriendID     primitive.ObjectID
+	FriendPostID primitive.ObjectID
+	Password     string
+	PostID       primitive.ObjectID
+	Token        string
+	UserID       primitive.ObjectID
+}
+
+// DeleteAccount creates a user and a friend that follow each other. The user has a post the friend saved to a
+// collection, and a comment with a reply of the friend on the friend's post
+func DeleteAccount() (*AccountMockResult, error) {
+	password := "123456"
+	user := &models.User{Email: "user@gmail.com", Password: password, Username: "user"}
+	user.NormalizeFields(true)
+	user.FollowersCount = 1
+	user.FollowingCount = 1
+	user.PostsCount = 1
+	err := user.HashPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	friend := &models.User{Email: "friend@gmail.com", Username: "friend"}
+	friend.NormalizeFields(true)
+	friend.FollowersCount = 1
+	friend.FollowingCount = 1
+	friend.PostsCount = 1
+
+	post := &models.Post{ID: primitive.NewObjectID(), CreatedAt: time.Now(), UserID: user.ID}
+	friendPost := &models.Post{ID: primitive.NewObjectID(), CommentsCount: 1, CreatedAt: time.Now(), RepliesCount: 1, UserID: friend.ID}
+	user.Posts = models.MapPostsToUserSubDocuments(*post)
+	friend.Posts = models.MapPostsToUserSubDocuments(*friendPost)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.InsertMany(context.Background(), bson.A{user, friend})
+	if err != nil {
+		return nil, err
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), bson.A{post, friendPost})
+	if err != nil {
+		return nil, err
+	}
+
+	follows := bson.A{
+		models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: friend.ID, FollowerID: user.ID},
+		models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: user.ID, FollowerID: friend.ID},
+	}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{ID: primitive.NewObjectID(), Message: "Comment", PostID: friendPost.ID, RepliesCount: 1, UserID: user.ID}
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertOne(context.Background(), comment)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &models.Reply{ID: primitive.NewObjectID(), Message: "Reply", PostID: friendPost.ID, ReplyToID: comment.ID, UserID: friend.ID}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	_, err = repliesCollection.InsertOne(context.Background(), reply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.SavePost(context.Background(), friend.ID, friendPost.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.SavedCollection{Name: "Saved"}
+	collection.NormalizeFields(friend.ID)
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.InsertOne(context.Background(), collection)
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.AddPostToSavedCollection(context.Background(), friend.ID, collection.ID, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := user.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountMockResult{
+		CollectionID: collection.ID,
+		FriendID:     friend.ID,
+		FriendPostID: friendPost.ID,
+		Password:     password,
+		PostID:       post.ID,
+		Token:        token,
+		UserID:       user.ID,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BlockRouteMockResult struct {
+	BlockedID primitive.ObjectID
+	BlockerID primitive.ObjectID
+	Token     string
+	UserID    primitive.ObjectID
+	ViewerID  primitive.ObjectID
+}
+
+// BlockUser creates the authenticated viewer, a user that follows the viewer back, a user the viewer already blocked
+// and a user that already blocked the viewer
+func BlockUser() (*BlockRouteMockResult, error) {
+	viewer := &models.User{Email: "viewer@gmail.com", Username: "viewer", FollowersCount: 1, FollowingCount: 1}
+	viewer.NormalizeFields(true)
+
+	user := &models.User{Email: "user@gmail.com", Username: "user", FollowersCount: 1, FollowingCount: 1}
+	user.NormalizeFields(true)
+
+	blocked := &models.User{Email: "blocked@gmail.com", Username: "blocked"}
+	blocked.NormalizeFields(true)
+
+	blocker := &models.User{Email: "blocker@gmail.com", Username: "blocker"}
+	blocker.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{viewer, user, blocked, blocker})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	follows := bson.A{
+		models.Follow{ID: primitive.NewObjectID(), CreatedAt: now, FolloweeID: user.ID, FollowerID: viewer.ID},
+		models.Follow{ID: primitive.NewObjectID(), CreatedAt: now, FolloweeID: viewer.ID, FollowerID: user.ID},
+	}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := bson.A{
+		models.Block{ID: primitive.NewObjectID(), BlockerID: viewer.ID, CreatedAt: now, UserID: blocked.ID},
+		models.Block{ID: primitive.NewObjectID(), BlockerID: blocker.ID, CreatedAt: now, UserID: viewer.ID},
+	}
+	blocksCollection := services.GetMongoDBCollection(config.BlocksCollection)
+	_, err = blocksCollection.InsertMany(context.Background(), blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := viewer.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockRouteMockResult{BlockedID: blocked.ID, BlockerID: blocker.ID, Token: token, UserID: user.ID, ViewerID: viewer.ID}, nil
+}
@@ -0,0 +1,84 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SavedCollectionRouteMockResult struct {
+	CollectionID      primitive.ObjectID
+	EmptyCollectionID primitive.ObjectID
+	OtherCollectionID primitive.ObjectID
+	PostID            primitive.ObjectID
+	SavedPostID       primitive.ObjectID
+	Token             string
+	UserID            primitive.ObjectID
+}
+
+// SavedCollections creates the authenticated user with the collections "Favorites", holding SavedPostID, and "Later",
+// which is empty, another post the user has not saved and a collection of another user
+func SavedCollections() (*SavedCollectionRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	other := &models.User{Email: "other@gmail.com", Username: "other"}
+	other.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, other})
+	if err != nil {
+		return nil, err
+	}
+
+	savedPost := &models.Post{}
+	savedPost.NormalizeFields(other.ID)
+
+	post := &models.Post{}
+	post.NormalizeFields(other.ID)
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), bson.A{savedPost, post})
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.SavedCollection{Name: "Favorites"}
+	collection.NormalizeFields(authUser.ID)
+
+	emptyCollection := &models.SavedCollection{Name: "Later"}
+	emptyCollection.NormalizeFields(authUser.ID)
+
+	otherCollection := &models.SavedCollection{Name: "Favorites"}
+	otherCollection.NormalizeFields(other.ID)
+
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.InsertMany(context.Background(), bson.A{collection, emptyCollection, otherCollection})
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.AddPostToSavedCollection(context.Background(), authUser.ID, collection.ID, savedPost.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SavedCollectionRouteMockResult{
+		CollectionID:      collection.ID,
+		EmptyCollectionID: emptyCollection.ID,
+		OtherCollectionID: otherCollection.ID,
+		PostID:            post.ID,
+		SavedPostID:       savedPost.ID,
+		Token:             token,
+		UserID:            authUser.ID,
+	}, nil
+}
@@ -0,0 +1,238 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommentRouteMockResult struct {
+	CommentID      primitive.ObjectID
+	CommentIDs     []primitive.ObjectID
+	FollowerToken  string
+	OtherCommentID primitive.ObjectID
+	OwnerToken     string
+	PostID         primitive.ObjectID
+	StrangerToken  string
+	Token          string
+}
+
+// DeleteComment creates a post with two comments by the same user, two replies to CommentID and one reply to
+// OtherCommentID, plus a newer comment on another post
+func DeleteComment() (*CommentRouteMockResult, error) {
+	owner := &models.User{Email: "owner@gmail.com", Username: "owner"}
+	owner.NormalizeFields(true)
+
+	commenter := &models.User{Email: "commenter@gmail.com", Username: "commenter"}
+	commenter.NormalizeFields(true)
+
+	stranger := &models.User{Email: "stranger@gmail.com", Username: "stranger"}
+	stranger.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{owner, commenter, stranger})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	post := &models.Post{ID: primitive.NewObjectID(), UserID: owner.ID, CommentsCount: 2, RepliesCount: 3}
+	otherPost := &models.Post{ID: primitive.NewObjectID(), UserID: owner.ID, CommentsCount: 1, Comments: []models.Comment{}}
+	comment := models.Comment{ID: primitive.NewObjectID(), CreatedAt: now.Add(-2 * time.Minute), Message: "First", PostID: post.ID, RepliesCount: 2, UserID: commenter.ID}
+	otherComment := models.Comment{ID: primitive.NewObjectID(), CreatedAt: now.Add(-time.Minute), Message: "Second", PostID: post.ID, RepliesCount: 1, UserID: commenter.ID}
+	otherPostComment := models.Comment{ID: primitive.NewObjectID(), CreatedAt: now, Message: "Third", PostID: otherPost.ID, UserID: commenter.ID}
+	post.Comments = []models.Comment{otherComment, comment}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), bson.A{post, otherPost})
+	if err != nil {
+		return nil, err
+	}
+
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertMany(context.Background(), bson.A{comment, otherComment, otherPostComment})
+	if err != nil {
+		return nil, err
+	}
+
+	replies := bson.A{
+		models.Reply{ID: primitive.NewObjectID(), Message: "Reply", PostID: post.ID, ReplyToID: comment.ID, UserID: owner.ID},
+		models.Reply{ID: primitive.NewObjectID(), Message: "Reply", PostID: post.ID, ReplyToID: comment.ID, UserID: stranger.ID},
+		models.Reply{ID: primitive.NewObjectID(), Message: "Reply", PostID: post.ID, ReplyToID: otherComment.ID, UserID: owner.ID},
+	}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	_, err = repliesCollection.InsertMany(context.Background(), replies)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := commenter.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ownerToken, err := owner.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	strangerToken, err := stranger.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentRouteMockResult{
+		CommentID:      comment.ID,
+		OtherCommentID: otherComment.ID,
+		OwnerToken:     ownerToken,
+		PostID:         post.ID,
+		StrangerToken:  strangerToken,
+		Token:          token,
+	}, nil
+}
+
+// UpdateComment creates a post of the owner with a comment by the commenter, whose token is Token, and a stranger
+func UpdateComment() (*CommentRouteMockResult, error) {
+	owner := &models.User{Email: "owner@gmail.com", Username: "owner"}
+	owner.NormalizeFields(true)
+
+	commenter := &models.User{Email: "commenter@gmail.com", Username: "commenter"}
+	commenter.NormalizeFields(true)
+
+	stranger := &models.User{Email: "stranger@gmail.com", Username: "stranger"}
+	stranger.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{owner, commenter, stranger})
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.Post{CommentsCount: 1}
+	post.NormalizeFields(owner.ID)
+
+	comment := &models.Comment{Message: "Comment", PostID: post.ID.Hex()}
+	err = comment.NormalizeFields(commenter.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertOne(context.Background(), post)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertOne(context.Background(), comment)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := commenter.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ownerToken, err := owner.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	strangerToken, err := stranger.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentRouteMockResult{
+		CommentID:     comment.ID,
+		OwnerToken:    ownerToken,
+		PostID:        post.ID,
+		StrangerToken: strangerToken,
+		Token:         token,
+	}, nil
+}
+
+// ModerateComments creates a post of the owner with five comments by a follower of the owner, oldest first in
+// CommentIDs, and a stranger that does not follow the owner
+func ModerateComments() (*CommentRouteMockResult, error) {
+	owner := &models.User{Email: "owner@gmail.com", Username: "owner", FollowersCount: 1}
+	owner.NormalizeFields(true)
+
+	follower := &models.User{Email: "follower@gmail.com", Username: "follower", FollowingCount: 1}
+	follower.NormalizeFields(true)
+
+	stranger := &models.User{Email: "stranger@gmail.com", Username: "stranger"}
+	stranger.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{owner, follower, stranger})
+	if err != nil {
+		return nil, err
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: owner.ID, FollowerID: follower.ID}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.Post{CommentsCount: 5}
+	post.NormalizeFields(owner.ID)
+
+	now := time.Now()
+	comments := bson.A{}
+	commentIds := []primitive.ObjectID{}
+	for i := 0; i < post.CommentsCount; i++ {
+		comment := models.Comment{
+			ID:        primitive.NewObjectID(),
+			CreatedAt: now.Add(time.Duration(i-post.CommentsCount) * time.Minute),
+			Message:   "Comment",
+			PostID:    post.ID,
+			UserID:    follower.ID,
+		}
+		comments = append(comments, comment)
+		commentIds = append(commentIds, comment.ID)
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertOne(context.Background(), post)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertMany(context.Background(), comments)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerToken, err := owner.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	followerToken, err := follower.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	strangerToken, err := stranger.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentRouteMockResult{
+		CommentIDs:    commentIds,
+		FollowerToken: followerToken,
+		OwnerToken:    ownerToken,
+		PostID:        post.ID,
+		StrangerToken: strangerToken,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ConversationRouteMockResult struct {
+	FollowerID primitive.ObjectID
+	StrangerID primitive.ObjectID
+	Token      string
+	UserID     primitive.ObjectID
+}
+
+// CreateConversation creates a user, one of the user's followers and a user that does not follow the user
+func CreateConversation() (*ConversationRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	follower := &models.User{Email: "follower@gmail.com", Username: "follower"}
+	follower.NormalizeFields(true)
+
+	stranger := &models.User{Email: "stranger@gmail.com", Username: "stranger"}
+	stranger.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, follower, stranger})
+	if err != nil {
+		return nil, err
+	}
+
+	follow := models.Follow{
+		ID:         primitive.NewObjectID(),
+		CreatedAt:  time.Now(),
+		FolloweeID: authUser.ID,
+		FollowerID: follower.ID,
+	}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConversationRouteMockResult{
+		FollowerID: follower.ID,
+		StrangerID: stranger.ID,
+		Token:      token,
+		UserID:     authUser.ID,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CounterJobMockResult struct {
+	CommentID primitive.ObjectID
+	PostID    primitive.ObjectID
+	UserID    primitive.ObjectID
+}
+
+// ReconcileCounters creates a user followed by one other user with one post, one comment and one reply, where the
+// user's followersCount and postsCount, the post's commentsCount and the comment's repliesCount have drifted
+func ReconcileCounters() (*CounterJobMockResult, error) {
+	user := &models.User{Email: "user@gmail.com", Username: "user"}
+	user.NormalizeFields(true)
+	user.FollowersCount = 3
+	user.PostsCount = -1
+
+	follower := &models.User{Email: "follower@gmail.com", Username: "follower"}
+	follower.NormalizeFields(true)
+	follower.FollowingCount = 1
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{user, follower})
+	if err != nil {
+		return nil, err
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: user.ID, FollowerID: follower.ID}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.Post{ID: primitive.NewObjectID(), UserID: user.ID, CommentsCount: 5, RepliesCount: 1}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertOne(context.Background(), post)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{ID: primitive.NewObjectID(), Message: "Comment", PostID: post.ID, UserID: follower.ID}
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertOne(context.Background(), comment)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &models.Reply{ID: primitive.NewObjectID(), Message: "Reply", PostID: post.ID, ReplyToID: comment.ID, UserID: user.ID}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	_, err = repliesCollection.InsertOne(context.Background(), reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CounterJobMockResult{CommentID: comment.ID, PostID: post.ID, UserID: user.ID}, nil
+}
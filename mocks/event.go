@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventRouteMockResult struct {
+	BlockedID     primitive.ObjectID
+	BlockedPostID primitive.ObjectID
+	CommenterID   primitive.ObjectID
+	PostID        primitive.ObjectID
+	Token         string
+	UserID        primitive.ObjectID
+}
+
+// StreamEvents creates the authenticated user, a public owner with a post, a commenter, and a blocked user with a
+// post of their own
+func StreamEvents() (*EventRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	owner := &models.User{Email: "owner@gmail.com", Username: "owner", PostsCount: 1}
+	owner.NormalizeFields(true)
+
+	commenter := &models.User{Email: "commenter@gmail.com", Username: "commenter"}
+	commenter.NormalizeFields(true)
+
+	blocked := &models.User{Email: "blocked@gmail.com", Username: "blocked", PostsCount: 1}
+	blocked.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, owner, commenter, blocked})
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.Post{}
+	post.NormalizeFields(owner.ID)
+	blockedPost := &models.Post{}
+	blockedPost.NormalizeFields(blocked.ID)
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), bson.A{post, blockedPost})
+	if err != nil {
+		return nil, err
+	}
+
+	block := models.Block{ID: primitive.NewObjectID(), BlockerID: authUser.ID, CreatedAt: time.Now(), UserID: blocked.ID}
+	blocksCollection := services.GetMongoDBCollection(config.BlocksCollection)
+	_, err = blocksCollection.InsertOne(context.Background(), block)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventRouteMockResult{
+		BlockedID:     blocked.ID,
+		BlockedPostID: blockedPost.ID,
+		CommenterID:   commenter.ID,
+		PostID:        post.ID,
+		Token:         token,
+		UserID:        authUser.ID,
+	}, nil
+}
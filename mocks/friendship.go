@@ -0,0 +1,188 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FriendshipRouteMockResult struct {
+	FollowerID primitive.ObjectID
+	OtherID    primitive.ObjectID
+	Token      string
+	UserID     primitive.ObjectID
+}
+
+// FollowUser creates the authenticated user, a public user to follow and another user that already follows it
+func FollowUser() (*FriendshipRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	user := &models.User{Email: "user@gmail.com", Username: "user", FollowersCount: 1}
+	user.NormalizeFields(true)
+
+	other := &models.User{Email: "other@gmail.com", Username: "other", FollowingCount: 1}
+	other.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, user, other})
+	if err != nil {
+		return nil, err
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: user.ID, FollowerID: other.ID}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendshipRouteMockResult{FollowerID: authUser.ID, OtherID: other.ID, Token: token, UserID: user.ID}, nil
+}
+
+// MigrateFollows stores friendships the way user_details buckets used to: UserID is followed by FollowerID and then
+// by OtherID, and UserID's bucket also holds a saved post
+func MigrateFollows() (*FriendshipRouteMockResult, error) {
+	userId := primitive.NewObjectID()
+	followerId := primitive.NewObjectID()
+	otherId := primitive.NewObjectID()
+	now := time.Now()
+
+	buckets := bson.A{
+		bson.M{"_id": primitive.NewObjectID(), "createdAt": now, "userId": userId, "followers": bson.A{otherId, followerId}, "followersCount": 2, "savedPosts": bson.A{primitive.NewObjectID()}, "savedPostsCount": 1},
+		bson.M{"_id": primitive.NewObjectID(), "createdAt": now, "userId": followerId, "following": bson.A{userId}, "followingCount": 1, "savedPosts": bson.A{}, "savedPostsCount": 0},
+		bson.M{"_id": primitive.NewObjectID(), "createdAt": now, "userId": otherId, "following": bson.A{userId}, "followingCount": 1},
+	}
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	_, err := userDetailsCollection.InsertMany(context.Background(), buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendshipRouteMockResult{FollowerID: followerId, OtherID: otherId, UserID: userId}, nil
+}
+
+// GetMutualFollowers creates the authenticated user following UserID and three other users, two of which follow
+// UserID. A third follower of UserID is not followed by the authenticated user
+func GetMutualFollowers() (*FriendshipRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	user := &models.User{Email: "user@gmail.com", Username: "user"}
+	user.NormalizeFields(true)
+
+	users := bson.A{authUser, user}
+	follows := bson.A{models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: user.ID, FollowerID: authUser.ID}}
+	for index, username := range []string{"mutual1", "mutual2", "followed", "stranger"} {
+		other := &models.User{Email: username + "@gmail.com", Username: username}
+		other.NormalizeFields(true)
+		users = append(users, other)
+
+		if username != "stranger" {
+			follows = append(follows, models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: other.ID, FollowerID: authUser.ID})
+		}
+
+		if username != "followed" {
+			createdAt := time.Now().Add(time.Duration(index) * time.Second)
+			follows = append(follows, models.Follow{ID: primitive.NewObjectID(), CreatedAt: createdAt, FolloweeID: user.ID, FollowerID: other.ID})
+		}
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), users)
+	if err != nil {
+		return nil, err
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendshipRouteMockResult{FollowerID: authUser.ID, Token: token, UserID: user.ID}, nil
+}
+
+// RemoveFollower creates the authenticated user followed by FollowerID, which has one of the user's posts in its timeline
+func RemoveFollower() (*FriendshipRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser", FollowersCount: 1}
+	authUser.NormalizeFields(true)
+
+	follower := &models.User{Email: "follower@gmail.com", Username: "follower", FollowingCount: 1}
+	follower.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, follower})
+	if err != nil {
+		return nil, err
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: authUser.ID, FollowerID: follower.ID}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return nil, err
+	}
+
+	post := models.Post{}
+	post.NormalizeFields(authUser.ID)
+	err = models.AddPostsToTimelines(context.Background(), bson.A{follower.ID}, post)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendshipRouteMockResult{FollowerID: follower.ID, Token: token, UserID: authUser.ID}, nil
+}
+
+// AddCloseFriend creates the authenticated user, UserID to add as a close friend and OtherID who has blocked the
+// authenticated user
+func AddCloseFriend() (*FriendshipRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	user := &models.User{Email: "user@gmail.com", Username: "user"}
+	user.NormalizeFields(true)
+
+	other := &models.User{Email: "other@gmail.com", Username: "other"}
+	other.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, user, other})
+	if err != nil {
+		return nil, err
+	}
+
+	block := models.Block{ID: primitive.NewObjectID(), BlockerID: other.ID, CreatedAt: time.Now(), UserID: authUser.ID}
+	blocksCollection := services.GetMongoDBCollection(config.BlocksCollection)
+	_, err = blocksCollection.InsertOne(context.Background(), block)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FriendshipRouteMockResult{FollowerID: authUser.ID, OtherID: other.ID, Token: token, UserID: user.ID}, nil
+}
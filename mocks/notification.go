@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetNotifications creates a user that has muted follow notifications and
+// receives comments from two other users on the same post
+func GetNotifications() (string, error) {
+	authUser := &models.User{
+		Email:              "authuser@gmail.com",
+		Username:           "authuser",
+		MutedNotifications: []string{models.FollowNotification},
+	}
+	authUser.NormalizeFields(true)
+
+	firstActor := &models.User{Email: "firstactor@gmail.com", Username: "firstactor"}
+	firstActor.NormalizeFields(true)
+
+	secondActor := &models.User{Email: "secondactor@gmail.com", Username: "secondactor"}
+	secondActor.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, firstActor, secondActor})
+	if err != nil {
+		return "", err
+	}
+
+	postId := primitive.NewObjectID()
+	for _, actor := range []*models.User{firstActor, secondActor} {
+		err = models.CreateNotification(context.Background(), authUser.ID, actor.ID, models.CommentNotification, postId)
+		if err != nil {
+			return "", err
+		}
+
+		err = models.CreateNotification(context.Background(), authUser.ID, actor.ID, models.FollowNotification, authUser.ID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return authUser.GenerateAccessToken()
+}
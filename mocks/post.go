@@ -99,21 +99,65 @@ func DeletePost() (*PostRouteMockResult, error) {
 	return result, nil
 }
 
-func SavePost() (*PostRouteMockResult, error) {
-	post := models.Post{Caption: "Test", ID: primitive.NewObjectID()}
+// ArchivePost creates the authenticated user with one post, a comment on it and the post in the user's timeline
+func ArchivePost() (*PostRouteMockResult, error) {
+	userId := primitive.NewObjectID()
+	post := models.Post{Caption: "Test", CommentsCount: 1}
+	post.NormalizeFields(userId)
 	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
 	_, err := postsCollection.InsertOne(context.Background(), post)
 	if err != nil {
 		return nil, err
 	}
 
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err = commentsCollection.InsertOne(context.Background(), models.Comment{PostID: post.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.AddPostsToTimelines(context.Background(), bson.A{userId}, post)
+	if err != nil {
+		return nil, err
+	}
+
+	user := models.User{
+		ID:         userId,
+		Email:      "test@gmail.com",
+		Username:   "testuser",
+		Posts:      models.MapPostsToUserSubDocuments(post),
+		PostsCount: 1,
+	}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.InsertOne(context.Background(), user)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := user.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostRouteMockResult{Token: token, UserID: user.ID, PostID: post.ID}, nil
+}
+
+func SavePost() (*PostRouteMockResult, error) {
+	author := models.User{ID: primitive.NewObjectID(), Email: "author@gmail.com", Username: "author"}
 	user := models.User{
 		ID:       primitive.NewObjectID(),
 		Email:    "test@gmail.com",
 		Username: "testuser",
 	}
 	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
-	_, err = usersCollection.InsertOne(context.Background(), user)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{author, user})
+	if err != nil {
+		return nil, err
+	}
+
+	post := models.Post{Caption: "Test", ID: primitive.NewObjectID(), UserID: author.ID}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertOne(context.Background(), post)
 	if err != nil {
 		return nil, err
 	}
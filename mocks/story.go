@@ -0,0 +1,119 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StoryRouteMockResult struct {
+	StoryID     primitive.ObjectID
+	Token       string
+	ViewerToken string
+}
+
+// CreateStory creates a user with a live story that has been viewed by another user
+func CreateStory() (*StoryRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser"}
+	authUser.NormalizeFields(true)
+
+	viewer := &models.User{Email: "viewer@gmail.com", Username: "viewer"}
+	viewer.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{authUser, viewer})
+	if err != nil {
+		return nil, err
+	}
+
+	story := &models.Story{Image: "https://example.com/stories/image"}
+	story.NormalizeFields(authUser.ID)
+	storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+	_, err = storiesCollection.InsertOne(context.Background(), story)
+	if err != nil {
+		return nil, err
+	}
+
+	err = models.RecordStoryView(context.Background(), story, viewer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	viewerToken, err := viewer.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoryRouteMockResult{
+		StoryID:     story.ID,
+		Token:       token,
+		ViewerToken: viewerToken,
+	}, nil
+}
+
+// GetStoriesFeed creates a user who follows three authors with a live story each, posted a minute apart with the
+// latest first, and has seen the story of the latest author
+func GetStoriesFeed() (*StoryRouteMockResult, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser", FollowingCount: 3}
+	authUser.NormalizeFields(true)
+
+	users := bson.A{authUser}
+	follows := bson.A{}
+	stories := []*models.Story{}
+	for i := 0; i < 3; i++ {
+		author := &models.User{Email: fmt.Sprintf("author%v@gmail.com", i), Username: fmt.Sprintf("author%v", i), FollowersCount: 1}
+		author.NormalizeFields(true)
+		users = append(users, author)
+
+		follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: author.ID, FollowerID: authUser.ID}
+		follows = append(follows, follow)
+
+		story := &models.Story{Image: "https://example.com/stories/image"}
+		story.NormalizeFields(author.ID)
+		story.CreatedAt = story.CreatedAt.Add(time.Duration(-i) * time.Minute)
+		stories = append(stories, story)
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), users)
+	if err != nil {
+		return nil, err
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return nil, err
+	}
+
+	storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+	for _, story := range stories {
+		_, err = storiesCollection.InsertOne(context.Background(), story)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = models.RecordStoryView(context.Background(), stories[0], authUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoryRouteMockResult{StoryID: stories[0].ID, Token: token}, nil
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TimelineJobMockResult struct {
+	DroppedID      primitive.ObjectID
+	FanOutOnReadID primitive.ObjectID
+	UserID         primitive.ObjectID
+}
+
+// BackfillTimelines creates a user with a post and no timeline who follows an author with two posts, an author that
+// was fanned out on read but dropped back under the limit, and an author over the limit, each with a post
+func BackfillTimelines() (*TimelineJobMockResult, error) {
+	user := &models.User{Email: "user@gmail.com", Username: "user", FollowingCount: 3, PostsCount: 1}
+	user.NormalizeFields(true)
+
+	author := &models.User{Email: "author@gmail.com", Username: "author", FollowersCount: 1, PostsCount: 2}
+	author.NormalizeFields(true)
+
+	dropped := &models.User{Email: "dropped@gmail.com", Username: "dropped", FanOutOnRead: true, FollowersCount: 1, PostsCount: 1}
+	dropped.NormalizeFields(true)
+
+	fanOutOnRead := &models.User{Email: "popular@gmail.com", Username: "popular", FollowersCount: config.TimelineFanOutLimit + 1, PostsCount: 1}
+	fanOutOnRead.NormalizeFields(true)
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), bson.A{user, author, dropped, fanOutOnRead})
+	if err != nil {
+		return nil, err
+	}
+
+	posts := bson.A{}
+	for _, userId := range []primitive.ObjectID{user.ID, author.ID, author.ID, dropped.ID, fanOutOnRead.ID} {
+		post := &models.Post{}
+		post.NormalizeFields(userId)
+		posts = append(posts, post)
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), posts)
+	if err != nil {
+		return nil, err
+	}
+
+	follows := bson.A{}
+	for _, followeeId := range []primitive.ObjectID{author.ID, dropped.ID, fanOutOnRead.ID} {
+		follows = append(follows, models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: followeeId, FollowerID: user.ID})
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimelineJobMockResult{DroppedID: dropped.ID, FanOutOnReadID: fanOutOnRead.ID, UserID: user.ID}, nil
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
@@ -27,16 +28,19 @@ func GetUserHomePosts() (string, error) {
 	userToFollow.NormalizeFields(true)
 
 	posts := bson.A{}
+	timelinePosts := []models.Post{}
 	for i := 0; i < userToFollow.PostsCount; i++ {
 		post := &models.Post{}
 		post.NormalizeFields(userToFollow.ID)
 		posts = append(posts, post)
+		timelinePosts = append(timelinePosts, *post)
 	}
 
 	for i := 0; i < authUser.PostsCount; i++ {
 		post := &models.Post{}
 		post.NormalizeFields(authUser.ID)
 		posts = append(posts, post)
+		timelinePosts = append(timelinePosts, *post)
 	}
 
 	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
@@ -45,14 +49,20 @@ func GetUserHomePosts() (string, error) {
 		return "", err
 	}
 
-	authUserDetails := models.UserDetails{
-		ID:        primitive.NewObjectID(),
-		UserID:    authUser.ID,
-		Following: bson.A{userToFollow.ID},
+	err = models.AddPostsToTimelines(context.Background(), bson.A{authUser.ID}, timelinePosts...)
+	if err != nil {
+		return "", err
 	}
 
-	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
-	_, err = userDetailsCollection.InsertOne(context.Background(), authUserDetails)
+	follow := models.Follow{
+		ID:         primitive.NewObjectID(),
+		CreatedAt:  time.Now(),
+		FolloweeID: userToFollow.ID,
+		FollowerID: authUser.ID,
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
 	if err != nil {
 		return "", err
 	}
@@ -96,3 +106,154 @@ func GetUserProfilePosts() (username string, err error) {
 	username = authUser.Username
 	return
 }
+
+// GetUserTaggedPosts creates "taggeduser" and posts by "author" that tag it twice, tag a longer username that
+// starts with it twice and do not tag anyone once
+func GetUserTaggedPosts() (username string, err error) {
+	author := &models.User{
+		Email:    "author@gmail.com",
+		Username: "author",
+	}
+	author.NormalizeFields(true)
+
+	taggedUser := &models.User{
+		Email:    "taggeduser@gmail.com",
+		Username: "taggeduser",
+	}
+	taggedUser.NormalizeFields(true)
+
+	posts := bson.A{}
+	captions := []string{"Hi @taggeduser", "With @taggeduser.", "Hi @taggeduser2", "With @taggeduser.smith", "Hi"}
+	for _, caption := range captions {
+		post := &models.Post{Caption: caption}
+		post.NormalizeFields(author.ID)
+		posts = append(posts, post)
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), posts)
+	if err != nil {
+		return
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.InsertMany(context.Background(), bson.A{author, taggedUser})
+	if err != nil {
+		return
+	}
+
+	username = taggedUser.Username
+	return
+}
+
+// GetUserSuggestions creates the authenticated user following friend1 and friend2. "mutual2" is followed by both
+// friends, "mutual1" by friend1 only and "follower" follows the authenticated user. "popular" and "dismissed" are
+// followed by nobody the authenticated user knows, and "dismissed" was dismissed from the suggestions
+func GetUserSuggestions() (string, error) {
+	users := map[string]*models.User{}
+	documents := bson.A{}
+	followersCounts := map[string]int{"popular": 100, "dismissed": 50}
+	for _, username := range []string{"authuser", "friend1", "friend2", "mutual1", "mutual2", "follower", "popular", "dismissed"} {
+		user := &models.User{Email: username + "@gmail.com", Username: username, FollowersCount: followersCounts[username]}
+		user.NormalizeFields(true)
+		users[username] = user
+		documents = append(documents, user)
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), documents)
+	if err != nil {
+		return "", err
+	}
+
+	follows := bson.A{}
+	for _, pair := range [][2]string{
+		{"authuser", "friend1"},
+		{"authuser", "friend2"},
+		{"friend1", "mutual1"},
+		{"friend1", "mutual2"},
+		{"friend2", "mutual2"},
+		{"follower", "authuser"},
+	} {
+		follows = append(follows, models.Follow{
+			ID:         primitive.NewObjectID(),
+			CreatedAt:  time.Now(),
+			FolloweeID: users[pair[1]].ID,
+			FollowerID: users[pair[0]].ID,
+		})
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertMany(context.Background(), follows)
+	if err != nil {
+		return "", err
+	}
+
+	err = models.DismissSuggestion(context.Background(), users["authuser"].ID, users["dismissed"].ID)
+	if err != nil {
+		return "", err
+	}
+
+	return users["authuser"].GenerateAccessToken()
+}
+
+// GetUserSavedPosts creates the authenticated user, who saved a post of each of "public", "followed" (private and
+// followed), "private" (private and not followed) and "blocked" (public and blocked by the authenticated user).
+// It returns the ids of the saved posts the authenticated user may see
+func GetUserSavedPosts() (string, []primitive.ObjectID, error) {
+	authUser := &models.User{Email: "authuser@gmail.com", Username: "authuser", FollowingCount: 1}
+	authUser.NormalizeFields(true)
+
+	users := map[string]*models.User{}
+	documents := bson.A{authUser}
+	posts := bson.A{}
+	postIds := map[string]primitive.ObjectID{}
+	for _, username := range []string{"public", "followed", "private", "blocked"} {
+		user := &models.User{Email: username + "@gmail.com", Username: username, PostsCount: 1}
+		user.NormalizeFields(true)
+		user.IsPrivate = username == "followed" || username == "private"
+		users[username] = user
+		documents = append(documents, user)
+
+		post := &models.Post{}
+		post.NormalizeFields(user.ID)
+		posts = append(posts, post)
+		postIds[username] = post.ID
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.InsertMany(context.Background(), documents)
+	if err != nil {
+		return "", nil, err
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.InsertMany(context.Background(), posts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: users["followed"].ID, FollowerID: authUser.ID}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.InsertOne(context.Background(), follow)
+	if err != nil {
+		return "", nil, err
+	}
+
+	block := models.Block{ID: primitive.NewObjectID(), BlockerID: authUser.ID, CreatedAt: time.Now(), UserID: users["blocked"].ID}
+	blocksCollection := services.GetMongoDBCollection(config.BlocksCollection)
+	_, err = blocksCollection.InsertOne(context.Background(), block)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, postId := range postIds {
+		err = models.SavePost(context.Background(), authUser.ID, postId)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	token, err := authUser.GenerateAccessToken()
+	return token, []primitive.ObjectID{postIds["public"], postIds["followed"]}, err
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Deactivated accounts, including those waiting to be deleted, are hidden along with their content as if they did
+// not exist. Logging in reactivates an account unless its deletion was requested
+
+// DeactivateUser returns false if the account was already deactivated
+func DeactivateUser(ctx context.Context, userId primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": userId, "deactivatedAt": nil}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	result, err := usersCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deactivatedAt": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func ReactivateUser(ctx context.Context, userId primitive.ObjectID) error {
+	filter := bson.M{"_id": userId, "deletionRequestedAt": nil}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"deactivatedAt": ""}})
+	return err
+}
+
+// RequestAccountDeletion deactivates the account and leaves it to the account deletion job
+func RequestAccountDeletion(ctx context.Context, user *User) error {
+	now := time.Now()
+	fields := bson.M{"deletionRequestedAt": now}
+	if user.DeactivatedAt == nil {
+		fields["deactivatedAt"] = now
+	}
+
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.UpdateByID(ctx, user.ID, bson.M{"$set": fields})
+	return err
+}
+
+func IsDeactivated(ctx context.Context, userId interface{}) (bool, error) {
+	filter := bson.M{"_id": userId, "deactivatedAt": bson.M{"$ne": nil}}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	count, err := usersCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+func FindDeactivatedUserIds(ctx context.Context) (bson.A, error) {
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	ids, err := usersCollection.Distinct(ctx, "_id", bson.M{"deactivatedAt": bson.M{"$ne": nil}})
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.A(ids), nil
+}
+
+// IsHidden reports whether userId is deactivated or has a block with viewerId. Anonymous viewers are represented by
+// primitive.NilObjectID
+func IsHidden(ctx context.Context, viewerId primitive.ObjectID, userId interface{}) (bool, error) {
+	if viewerId == userId {
+		return false, nil
+	}
+
+	deactivated, err := IsDeactivated(ctx, userId)
+	if err != nil || deactivated || viewerId.IsZero() {
+		return deactivated, err
+	}
+
+	return IsBlocked(ctx, viewerId, userId)
+}
+
+// FindHiddenUserIds returns the ids of the users that have a block with userId and of the deactivated accounts
+func FindHiddenUserIds(ctx context.Context, userId primitive.ObjectID) (bson.A, error) {
+	blockedIds, err := FindBlockedUserIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivatedIds, err := FindDeactivatedUserIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(blockedIds, deactivatedIds...), nil
+}
+
+// PurgeComment should be called within a transaction. It permanently removes the comment and its replies, and takes
+// them out of the counts of the post unless they were already taken out when they were soft deleted
+func PurgeComment(sessCtx mongo.SessionContext, comment *Comment) error {
+	filter := bson.M{"replyToId": comment.ID}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	liveReplies, err := repliesCollection.CountDocuments(sessCtx, ExcludeDeleted(bson.M{"replyToId": comment.ID}))
+	if err != nil {
+		return err
+	}
+
+	_, err = repliesCollection.DeleteMany(sessCtx, filter)
+	if err != nil {
+		return err
+	}
+
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	result, err := commentsCollection.DeleteOne(sessCtx, bson.M{"_id": comment.ID})
+	if err != nil || result.DeletedCount == 0 || comment.DeletedAt != nil {
+		return err
+	}
+
+	return updateCommentCounts(sessCtx, comment, -1, -liveReplies)
+}
+
+// PurgeReply should be called within a transaction. It permanently removes the reply and takes it out of the counts
+// of its comment and post unless it was already taken out when it was soft deleted
+func PurgeReply(sessCtx mongo.SessionContext, reply *Reply) error {
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.DeleteOne(sessCtx, bson.M{"_id": reply.ID})
+	if err != nil || result.DeletedCount == 0 || reply.DeletedAt != nil {
+		return err
+	}
+
+	return updateReplyCounts(sessCtx, reply, -1)
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var tagRegex = regexp.MustCompile(`@([a-z0-9_.]+)`)
+
+type Block struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	BlockerID interface{}        `bson:"blockerId" json:"blockerId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UserID    interface{}        `bson:"userId" json:"userId"`
+}
+
+// IsBlocked reports whether either user has blocked the other
+func IsBlocked(ctx context.Context, userId, otherUserId interface{}) (bool, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"blockerId": userId, "userId": otherUserId},
+			bson.M{"blockerId": otherUserId, "userId": userId},
+		},
+	}
+	collection := services.GetMongoDBCollection(config.BlocksCollection)
+	count, err := collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+// HasBlocked reports whether blockerId has blocked userId
+func HasBlocked(ctx context.Context, blockerId, userId interface{}) (bool, error) {
+	collection := services.GetMongoDBCollection(config.BlocksCollection)
+	count, err := collection.CountDocuments(ctx, bson.M{"blockerId": blockerId, "userId": userId}, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+// FindBlockedUserIds returns the ids of the users that userId has blocked or has been blocked by
+func FindBlockedUserIds(ctx context.Context, userId primitive.ObjectID) (bson.A, error) {
+	ids := bson.A{}
+	if userId.IsZero() {
+		return ids, nil
+	}
+
+	filter := bson.M{"$or": bson.A{bson.M{"blockerId": userId}, bson.M{"userId": userId}}}
+	collection := services.GetMongoDBCollection(config.BlocksCollection)
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []Block{}
+	err = cursor.All(ctx, &blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		if block.BlockerID == userId {
+			ids = append(ids, block.UserID)
+		} else {
+			ids = append(ids, block.BlockerID)
+		}
+	}
+
+	return ids, nil
+}
+
+func findTaggedUsernames(text string) bson.A {
+	usernames := bson.A{}
+	for _, match := range tagRegex.FindAllStringSubmatch(text, -1) {
+		usernames = append(usernames, match[1])
+	}
+
+	return usernames
+}
+
+// TaggedUsernameRegex is the MongoDB regex that matches text tagging username, but not a longer username that starts
+// with it. A trailing period ends the sentence, as in FindMentions
+func TaggedUsernameRegex(username string) string {
+	return "@" + regexp.QuoteMeta(username) + `(?![a-z0-9_]|\.[a-z0-9_])`
+}
+
+// FindBlockedTaggedUsernames returns the usernames tagged in the text that have a block with userId
+func FindBlockedTaggedUsernames(ctx context.Context, userId primitive.ObjectID, text string) ([]string, error) {
+	usernames := findTaggedUsernames(text)
+	blockedUsernames := []string{}
+	if len(usernames) == 0 {
+		return blockedUsernames, nil
+	}
+
+	blockedIds, err := FindBlockedUserIds(ctx, userId)
+	if err != nil || len(blockedIds) == 0 {
+		return blockedUsernames, err
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": blockedIds}, "username": bson.M{"$in": usernames}}
+	findOptions := options.Find().SetProjection(bson.M{"username": 1})
+	collection := services.GetMongoDBCollection(config.UsersCollection)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []User{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		blockedUsernames = append(blockedUsernames, user.Username)
+	}
+
+	return blockedUsernames, nil
+}
+
+// CreateBlock should be called within a transaction. It removes follows, follow requests and close friends in
+// both directions
+func CreateBlock(sessCtx mongo.SessionContext, blockerId, userId primitive.ObjectID) error {
+	block := Block{
+		ID:        primitive.NewObjectID(),
+		BlockerID: blockerId,
+		CreatedAt: time.Now(),
+		UserID:    userId,
+	}
+	filter := bson.M{"blockerId": blockerId, "userId": userId}
+	collection := services.GetMongoDBCollection(config.BlocksCollection)
+	_, err := collection.UpdateOne(sessCtx, filter, bson.M{"$setOnInsert": block}, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	pairs := [][2]primitive.ObjectID{{blockerId, userId}, {userId, blockerId}}
+	for _, pair := range pairs {
+		err = DeleteFollow(sessCtx, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+
+		_, err = DeleteFollowRequest(sessCtx, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+
+		err = RemoveCloseFriend(sessCtx, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func DeleteBlock(ctx context.Context, blockerId, userId primitive.ObjectID) (bool, error) {
+	collection := services.GetMongoDBCollection(config.BlocksCollection)
+	result, err := collection.DeleteOne(ctx, bson.M{"blockerId": blockerId, "userId": userId})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount == 1, nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A CloseFriend puts FriendID on the close friends list of UserID
+type CloseFriend struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	FriendID  primitive.ObjectID `bson:"friendId" json:"friendId"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+}
+
+// AddCloseFriend does nothing if friendId is already on the list
+func AddCloseFriend(ctx context.Context, userId, friendId primitive.ObjectID) error {
+	closeFriend := CloseFriend{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: time.Now(),
+		FriendID:  friendId,
+		UserID:    userId,
+	}
+	filter := bson.M{"userId": userId, "friendId": friendId}
+	collection := services.GetMongoDBCollection(config.CloseFriendsCollection)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": closeFriend}, options.Update().SetUpsert(true))
+	return err
+}
+
+func RemoveCloseFriend(ctx context.Context, userId, friendId primitive.ObjectID) error {
+	collection := services.GetMongoDBCollection(config.CloseFriendsCollection)
+	_, err := collection.DeleteOne(ctx, bson.M{"userId": userId, "friendId": friendId})
+	return err
+}
+
+func IsCloseFriend(ctx context.Context, userId, friendId interface{}) (bool, error) {
+	collection := services.GetMongoDBCollection(config.CloseFriendsCollection)
+	count, err := collection.CountDocuments(ctx, bson.M{"userId": userId, "friendId": friendId}, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+// CloseFriendLookupStage looks up, under as, the entry putting friendId on the close friends list of the user whose id is
+// stored under userField. as is empty when friendId is not on that list
+func CloseFriendLookupStage(userField string, friendId interface{}, as string) bson.M {
+	return bson.M{
+		"$lookup": bson.M{
+			"from": config.CloseFriendsCollection,
+			"let":  bson.M{"userId": "$" + userField},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$userId", "$$userId"}}, "friendId": friendId}},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": as,
+		},
+	}
+}
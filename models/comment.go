@@ -16,10 +16,22 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// CommentEdit is a prior version of an edited comment or reply
+type CommentEdit struct {
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	Message   string    `bson:"message" json:"message"`
+}
+
 type Comment struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty" `
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	DeletedAt    *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy    interface{}        `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	EditedAt     *time.Time         `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+	Edits        []CommentEdit      `bson:"edits,omitempty" json:"edits,omitempty"`
+	IsHidden     bool               `bson:"isHidden,omitempty" json:"isHidden,omitempty"`
 	LikesCount   int                `bson:"likesCount" json:"likesCount"`
+	Mentions     []Mention          `bson:"mentions,omitempty" json:"mentions,omitempty"`
 	Message      string             `bson:"message" json:"message" binding:"required"`
 	PostID       interface{}        `bson:"postId" json:"postId" binding:"object_id"`
 	RepliesCount int                `bson:"repliesCount" json:"repliesCount"`
@@ -30,7 +42,13 @@ type Comment struct {
 func (comment *Comment) NormalizeFields(userId primitive.ObjectID) error {
 	var err error
 	comment.CreatedAt = time.Now()
+	comment.DeletedAt = nil
+	comment.DeletedBy = nil
+	comment.EditedAt = nil
+	comment.Edits = nil
 	comment.ID = primitive.NewObjectID()
+	comment.IsHidden = false
+	comment.Mentions = nil
 	comment.UserID = userId
 	comment.PostID, err = primitive.ObjectIDFromHex(fmt.Sprintf("%v", comment.PostID))
 	if err != nil {
@@ -39,6 +57,16 @@ func (comment *Comment) NormalizeFields(userId primitive.ObjectID) error {
 	return nil
 }
 
+// Edit keeps the current message in the edit history before replacing it
+func (comment *Comment) Edit(message string) {
+	comment.Edits, comment.EditedAt = appendEdit(comment.Edits, comment.EditedAt, comment.CreatedAt, comment.Message)
+	comment.Message = message
+}
+
+func (comment *Comment) IsEditable() bool {
+	return time.Since(comment.CreatedAt) <= config.CommentEditWindow
+}
+
 func (comment *Comment) SetUser(user *User) {
 	comment.UserID = nil
 	comment.User = bson.M{
@@ -78,3 +106,18 @@ func FindComment(ctx context.Context, filter interface{}, options ...*options.Fi
 		Comment: comment,
 	}
 }
+
+// AuthorOnlyEditsStage drops the prior versions of the comments or replies that the viewer did not write
+func AuthorOnlyEditsStage(viewerId primitive.ObjectID) bson.M {
+	isAuthor := bson.M{"$eq": bson.A{"$userId", viewerId}}
+	return bson.M{"$addFields": bson.M{"edits": bson.M{"$cond": bson.A{isAuthor, "$edits", "$$REMOVE"}}}}
+}
+
+func appendEdit(edits []CommentEdit, editedAt *time.Time, createdAt time.Time, message string) ([]CommentEdit, *time.Time) {
+	if editedAt != nil {
+		createdAt = *editedAt
+	}
+
+	now := time.Now()
+	return append(edits, CommentEdit{CreatedAt: createdAt, Message: message}), &now
+}
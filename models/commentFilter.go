@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	nonWordRegex = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	// offensiveWords is the built-in list applied unless the user turns it off
+	offensiveWords = []string{
+		"asshole", "bastard", "bitch", "dickhead", "dumbass", "fuck", "fucking", "idiot", "kill yourself",
+		"kys", "loser", "moron", "motherfucker", "piece of shit", "shit", "slut", "stupid", "whore",
+	}
+)
+
+// CommentFilterSettings decide which comments on the user's posts are hidden until the user approves them
+type CommentFilterSettings struct {
+	HideOffensive *bool    `bson:"hideOffensive" json:"hideOffensive" binding:"required"`
+	Keywords      []string `bson:"keywords" json:"keywords" binding:"required,max=100,dive,min=1,max=50"`
+}
+
+func DefaultCommentFilterSettings() *CommentFilterSettings {
+	hideOffensive := true
+	return &CommentFilterSettings{HideOffensive: &hideOffensive, Keywords: []string{}}
+}
+
+// NormalizeFields trims and lowercases the keywords and drops duplicates
+func (settings *CommentFilterSettings) NormalizeFields() {
+	keywords := []string{}
+	seen := map[string]bool{}
+	for _, keyword := range settings.Keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && !seen[keyword] {
+			seen[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	settings.Keywords = keywords
+}
+
+// Matches reports whether the message contains one of the keywords as whole words, ignoring case and punctuation
+func (settings *CommentFilterSettings) Matches(message string) bool {
+	keywords := settings.Keywords
+	if settings.HideOffensive == nil || *settings.HideOffensive {
+		keywords = append(keywords[:len(keywords):len(keywords)], offensiveWords...)
+	}
+
+	text := normalizeFilterText(message)
+	for _, keyword := range keywords {
+		keyword = normalizeFilterText(keyword)
+		if keyword != "  " && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeFilterText(text string) string {
+	words := strings.Fields(nonWordRegex.ReplaceAllString(strings.ToLower(text), " "))
+	return " " + strings.Join(words, " ") + " "
+}
+
+func FindCommentFilterSettings(ctx context.Context, userId interface{}) (*CommentFilterSettings, error) {
+	findOneOptions := options.FindOne().SetProjection(bson.M{"commentFilters": 1})
+	findUserResult := FindUser(ctx, bson.M{"_id": userId}, findOneOptions)
+	if findUserResult.User == nil {
+		return nil, findUserResult.Error
+	}
+
+	if findUserResult.User.CommentFilters == nil {
+		return DefaultCommentFilterSettings(), nil
+	}
+
+	return findUserResult.User.CommentFilters, nil
+}
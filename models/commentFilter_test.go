@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentFilterSettingsMatches(t *testing.T) {
+	hideOffensive := false
+	settings := &CommentFilterSettings{HideOffensive: &hideOffensive, Keywords: []string{"spam", "follow back"}}
+
+	assert.True(t, settings.Matches("Great pic! SPAM."))
+	assert.True(t, settings.Matches("please   follow, back"))
+	assert.False(t, settings.Matches("spammer"))
+	assert.False(t, settings.Matches("you idiot"))
+}
+
+func TestDefaultCommentFilterSettingsMatchesOffensiveWords(t *testing.T) {
+	settings := DefaultCommentFilterSettings()
+
+	assert.True(t, settings.Matches("You IDIOT!"))
+	assert.False(t, settings.Matches("Lovely photo"))
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The comment preview is the list of the latest visible comments embedded in a post, so a post and its
+// first comments are read together. It is only changed through the functions in this file.
+
+// AddCommentToPreview puts a new comment at the top of the preview unless the comment is hidden
+func AddCommentToPreview(ctx context.Context, comment *Comment) error {
+	if comment.IsHidden {
+		return nil
+	}
+
+	previewComment := *comment
+	previewComment.Edits = nil
+	update := bson.M{
+		"$push": bson.M{"comments": bson.M{"$each": bson.A{previewComment}, "$position": 0, "$slice": config.CommonPaginationLength}},
+	}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err := postsCollection.UpdateByID(ctx, comment.PostID, update)
+	return err
+}
+
+// UpdateCommentInPreview copies an edited comment into the preview. A comment that has become hidden is removed.
+func UpdateCommentInPreview(ctx context.Context, comment *Comment) error {
+	if comment.IsHidden {
+		return RefreshCommentPreview(ctx, comment.PostID)
+	}
+
+	filter := bson.M{"_id": comment.PostID, "comments._id": comment.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"comments.$.editedAt":     comment.EditedAt,
+			"comments.$.mentions":     comment.Mentions,
+			"comments.$.message":      comment.Message,
+			"comments.$.repliesCount": comment.RepliesCount,
+		},
+	}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err := postsCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// IncrementPreviewRepliesCount keeps the repliesCount of a comment in the preview in sync with the comment
+func IncrementPreviewRepliesCount(ctx context.Context, postId, commentId interface{}, value int) error {
+	filter := bson.M{"_id": postId, "comments._id": commentId}
+	update := bson.M{"$inc": bson.M{"comments.$.repliesCount": value}}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err := postsCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// RefreshCommentPreview rebuilds the preview from the latest visible comments of the post. It is used when
+// comments leave the preview, so the next latest comments of the same post take their place.
+func RefreshCommentPreview(ctx context.Context, postId interface{}) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(config.CommonPaginationLength).
+		SetProjection(bson.M{"edits": 0})
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	cursor, err := commentsCollection.Find(ctx, ExcludeDeleted(bson.M{"postId": postId, "isHidden": bson.M{"$ne": true}}), findOptions)
+	if err != nil {
+		return err
+	}
+
+	comments := []Comment{}
+	err = cursor.All(ctx, &comments)
+	if err != nil {
+		return err
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.UpdateByID(ctx, postId, bson.M{"$set": bson.M{"comments": comments}})
+	return err
+}
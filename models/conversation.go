@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	AcceptedConversationStatus = "accepted"
+	DeclinedConversationStatus = "declined"
+	// Conversations started by users the member does not follow land in the member's message requests
+	PendingConversationStatus = "pending"
+)
+
+type ConversationMember struct {
+	LastReadAt time.Time          `bson:"lastReadAt" json:"lastReadAt"`
+	Status     string             `bson:"status" json:"status"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+}
+
+type Conversation struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt     time.Time            `bson:"createdAt" json:"createdAt"`
+	CreatorID     primitive.ObjectID   `bson:"creatorId" json:"creatorId"`
+	IsGroup       bool                 `bson:"isGroup" json:"isGroup"`
+	LastMessage   bson.M               `bson:"lastMessage,omitempty" json:"lastMessage,omitempty"`
+	LastMessageAt time.Time            `bson:"lastMessageAt" json:"lastMessageAt"`
+	MemberIDs     []primitive.ObjectID `bson:"memberIds" json:"memberIds"`
+	Members       []ConversationMember `bson:"members" json:"members"`
+	Name          string               `bson:"name,omitempty" json:"name,omitempty"`
+}
+
+// NewConversation accepts the conversation for the creator and for every member that follows the creator
+func NewConversation(ctx context.Context, creatorId primitive.ObjectID, userIds []primitive.ObjectID, name string) (*Conversation, error) {
+	now := time.Now()
+	conversation := &Conversation{
+		ID:            primitive.NewObjectID(),
+		CreatedAt:     now,
+		CreatorID:     creatorId,
+		IsGroup:       len(userIds) > 1,
+		LastMessageAt: now,
+		MemberIDs:     append([]primitive.ObjectID{creatorId}, userIds...),
+		Members:       []ConversationMember{{LastReadAt: now, Status: AcceptedConversationStatus, UserID: creatorId}},
+		Name:          name,
+	}
+
+	for _, userId := range userIds {
+		following, err := IsFollowing(ctx, userId, creatorId)
+		if err != nil {
+			return nil, err
+		}
+
+		status := PendingConversationStatus
+		if following {
+			status = AcceptedConversationStatus
+		}
+
+		conversation.Members = append(conversation.Members, ConversationMember{Status: status, UserID: userId})
+	}
+
+	return conversation, nil
+}
+
+func (conversation *Conversation) FindMember(userId primitive.ObjectID) *ConversationMember {
+	for index := range conversation.Members {
+		if conversation.Members[index].UserID == userId {
+			return &conversation.Members[index]
+		}
+	}
+
+	return nil
+}
+
+type FindConversationResult struct {
+	Conversation *Conversation
+	Error        error
+	ResponseBody interface{}
+	StatusCode   int
+}
+
+func FindConversation(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindConversationResult {
+	conversation := &Conversation{}
+	collection := services.GetMongoDBCollection(config.ConversationsCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(conversation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindConversationResult{
+			ResponseBody: gin.H{"message": "Conversation not found"},
+			StatusCode:   http.StatusNotFound,
+			Error:        err,
+		}
+	}
+
+	if err != nil {
+		return &FindConversationResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
+		}
+	}
+
+	return &FindConversationResult{
+		Conversation: conversation,
+	}
+}
+
+// UpdateConversationMember sets the fields of the member, e.g status or lastReadAt
+func UpdateConversationMember(ctx context.Context, conversationId, userId primitive.ObjectID, fields bson.M) (bool, error) {
+	set := bson.M{}
+	for field, value := range fields {
+		set["members.$."+field] = value
+	}
+
+	filter := bson.M{"_id": conversationId, "members.userId": userId}
+	collection := services.GetMongoDBCollection(config.ConversationsCollection)
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
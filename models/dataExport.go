@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CompletedDataExportStatus  = "completed"
+	ExpiredDataExportStatus    = "expired"
+	FailedDataExportStatus     = "failed"
+	PendingDataExportStatus    = "pending"
+	ProcessingDataExportStatus = "processing"
+)
+
+// A DataExport is a request of a user for a copy of their data. Pending exports are queued for the data export job,
+// which stores the ZIP under Key and notifies the user with a download link. The ZIP is removed once the link has
+// expired. A user has at most one pending export
+type DataExport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	ClaimedAt   *time.Time         `bson:"claimedAt,omitempty" json:"-"`
+	CompletedAt *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	Key         string             `bson:"key,omitempty" json:"-"`
+	Status      string             `bson:"status" json:"status"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId,omitempty"`
+}
+
+func (export *DataExport) NormalizeFields(userId primitive.ObjectID) {
+	export.ID = primitive.NewObjectID()
+	export.CreatedAt = time.Now()
+	export.Status = PendingDataExportStatus
+	export.UserID = userId
+}
+
+func (export *DataExport) GenerateKey() string {
+	return "exports/" + export.UserID.Hex() + "/" + export.ID.Hex() + ".zip"
+}
+
+// ClaimDataExport marks the oldest pending export as processing, so that no other API instance builds it too.
+// Exports left processing for longer than config.DataExportClaimTimeout, e.g by an instance that stopped, are claimed
+// again. It returns nil when there is nothing to claim
+func ClaimDataExport(ctx context.Context) (*DataExport, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": PendingDataExportStatus},
+			bson.M{"status": ProcessingDataExportStatus, "claimedAt": bson.M{"$lt": now.Add(-config.DataExportClaimTimeout)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"claimedAt": now, "status": ProcessingDataExportStatus}}
+	findOneAndUpdateOptions := options.FindOneAndUpdate().SetSort(bson.M{"createdAt": 1}).SetReturnDocument(options.After)
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+
+	export := &DataExport{}
+	err := dataExportsCollection.FindOneAndUpdate(ctx, filter, update, findOneAndUpdateOptions).Decode(export)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// IsDataExportInProgress reports whether the user has an export that is pending or being built
+func IsDataExportInProgress(ctx context.Context, userId interface{}) (bool, error) {
+	filter := bson.M{"userId": userId, "status": bson.M{"$in": bson.A{PendingDataExportStatus, ProcessingDataExportStatus}}}
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	count, err := dataExportsCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+// CompleteDataExport marks the export as completed and notifies its user with a link to download it
+func CompleteDataExport(ctx context.Context, export *DataExport, url string) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"completedAt": now, "key": export.GenerateKey(), "status": CompletedDataExportStatus}}
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	_, err := dataExportsCollection.UpdateByID(ctx, export.ID, update)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := now.Add(config.DataExportLinkTTL)
+	notification := &Notification{
+		ID:        primitive.NewObjectID(),
+		ActorIDs:  []interface{}{},
+		CreatedAt: now,
+		EntityID:  export.ID,
+		ExpiresAt: &expiresAt,
+		Type:      DataExportNotification,
+		UpdatedAt: now,
+		URL:       url,
+		UserID:    export.UserID,
+	}
+	notificationsCollection := services.GetMongoDBCollection(config.NotificationsCollection)
+	_, err = notificationsCollection.InsertOne(ctx, notification)
+	return err
+}
+
+func FailDataExport(ctx context.Context, export *DataExport) error {
+	update := bson.M{"$set": bson.M{"status": FailedDataExportStatus}}
+	dataExportsCollection := services.GetMongoDBCollection(config.DataExportsCollection)
+	_, err := dataExportsCollection.UpdateByID(ctx, export.ID, update)
+	return err
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Posts, comments and replies are soft deleted: their documents are kept with deletedAt and deletedBy set so the user
+// that deleted them can restore them within config.DeletedContentRetention, after which they are purged
+
+const (
+	CommentsDeletedContentType = "comments"
+	PostsDeletedContentType    = "posts"
+	RepliesDeletedContentType  = "replies"
+)
+
+// ExcludeDeleted adds the condition that leaves out soft deleted documents to the filter
+func ExcludeDeleted(filter bson.M) bson.M {
+	filter["deletedAt"] = nil
+	return filter
+}
+
+// RestorableFilter matches the documents deletedBy can still restore. Replies deleted along with their comment are
+// only restored with it
+func RestorableFilter(deletedBy interface{}) bson.M {
+	return bson.M{
+		"deletedAt":     bson.M{"$gt": time.Now().Add(-config.DeletedContentRetention)},
+		"deletedBy":     deletedBy,
+		"deletedWithId": nil,
+	}
+}
+
+func deletedFields(deletedBy interface{}) bson.M {
+	return bson.M{"deletedAt": time.Now(), "deletedBy": deletedBy}
+}
+
+// SoftDeletePost should be called within a transaction. The comments and replies of the post are left as they are
+// since they cannot be reached without it. It returns false if the post was already deleted
+func SoftDeletePost(sessCtx mongo.SessionContext, user *User, post *Post) (bool, error) {
+	filter := ExcludeDeleted(bson.M{"_id": post.ID})
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	result, err := postsCollection.UpdateOne(sessCtx, filter, bson.M{"$set": deletedFields(user.ID)})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	// Archived posts have already left the timelines, the preview and postsCount
+	if post.ArchivedAt != nil {
+		return true, nil
+	}
+
+	timelinesCollection := services.GetMongoDBCollection(config.TimelinesCollection)
+	_, err = timelinesCollection.DeleteMany(sessCtx, bson.M{"postId": post.ID})
+	if err != nil {
+		return false, err
+	}
+
+	return true, RemovePostFromUser(sessCtx, user, post.ID)
+}
+
+// RestorePost should be called within a transaction. It returns false if the post was not deleted. A post that was
+// archived when it was deleted stays archived
+func RestorePost(sessCtx mongo.SessionContext, user *User, post *Post) (bool, error) {
+	filter := bson.M{"_id": post.ID, "deletedAt": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	result, err := postsCollection.UpdateOne(sessCtx, filter, update)
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	post.DeletedAt = nil
+	post.DeletedBy = nil
+	if post.ArchivedAt != nil {
+		return true, nil
+	}
+
+	return true, republishPost(sessCtx, user, post)
+}
+
+// SoftDeleteComment should be called within a transaction. The replies of the comment are deleted along with it and
+// leave the counts of the comment and the post. It returns false if the comment was already deleted
+func SoftDeleteComment(sessCtx mongo.SessionContext, comment *Comment, deletedBy interface{}) (bool, error) {
+	fields := deletedFields(deletedBy)
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	result, err := commentsCollection.UpdateOne(sessCtx, ExcludeDeleted(bson.M{"_id": comment.ID}), bson.M{"$set": fields})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	fields["deletedWithId"] = comment.ID
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err = repliesCollection.UpdateMany(sessCtx, ExcludeDeleted(bson.M{"replyToId": comment.ID}), bson.M{"$set": fields})
+	if err != nil {
+		return false, err
+	}
+
+	return true, updateCommentCounts(sessCtx, comment, -1, -result.ModifiedCount)
+}
+
+// RestoreComment should be called within a transaction. It returns false if the comment was not deleted
+func RestoreComment(sessCtx mongo.SessionContext, comment *Comment) (bool, error) {
+	filter := bson.M{"_id": comment.ID, "deletedAt": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	result, err := commentsCollection.UpdateOne(sessCtx, filter, update)
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	update = bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": "", "deletedWithId": ""}}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err = repliesCollection.UpdateMany(sessCtx, bson.M{"deletedWithId": comment.ID}, update)
+	if err != nil {
+		return false, err
+	}
+
+	return true, updateCommentCounts(sessCtx, comment, 1, result.ModifiedCount)
+}
+
+func updateCommentCounts(sessCtx mongo.SessionContext, comment *Comment, comments int, replies int64) error {
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err := commentsCollection.UpdateByID(sessCtx, comment.ID, bson.M{"$inc": bson.M{"repliesCount": replies}})
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$inc": bson.M{"commentsCount": comments, "repliesCount": replies}}
+	if comments < 0 {
+		update["$pull"] = bson.M{"pinnedCommentIds": comment.ID}
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.UpdateByID(sessCtx, comment.PostID, update)
+	if err != nil {
+		return err
+	}
+
+	return RefreshCommentPreview(sessCtx, comment.PostID)
+}
+
+// SoftDeleteReply should be called within a transaction. It returns false if the reply was already deleted
+func SoftDeleteReply(sessCtx mongo.SessionContext, reply *Reply, deletedBy interface{}) (bool, error) {
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.UpdateOne(sessCtx, ExcludeDeleted(bson.M{"_id": reply.ID}), bson.M{"$set": deletedFields(deletedBy)})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	return true, updateReplyCounts(sessCtx, reply, -1)
+}
+
+// RestoreReply should be called within a transaction. It returns false if the reply was not deleted
+func RestoreReply(sessCtx mongo.SessionContext, reply *Reply) (bool, error) {
+	filter := bson.M{"_id": reply.ID, "deletedAt": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}
+	repliesCollection := services.GetMongoDBCollection(config.RepliesCollection)
+	result, err := repliesCollection.UpdateOne(sessCtx, filter, update)
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	return true, updateReplyCounts(sessCtx, reply, 1)
+}
+
+func updateReplyCounts(sessCtx mongo.SessionContext, reply *Reply, value int) error {
+	commentsCollection := services.GetMongoDBCollection(config.CommentsCollection)
+	_, err := commentsCollection.UpdateByID(sessCtx, reply.ReplyToID, bson.M{"$inc": bson.M{"repliesCount": value}})
+	if err != nil {
+		return err
+	}
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	_, err = postsCollection.UpdateByID(sessCtx, reply.PostID, bson.M{"$inc": bson.M{"repliesCount": value}})
+	if err != nil {
+		return err
+	}
+
+	return IncrementPreviewRepliesCount(sessCtx, reply.PostID, reply.ReplyToID, value)
+}
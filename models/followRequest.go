@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FollowRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	RequesterID interface{}        `bson:"requesterId" json:"requesterId"`
+	UserID      interface{}        `bson:"userId" json:"userId"`
+}
+
+// CreateFollowRequest does nothing if the request is still pending
+func CreateFollowRequest(ctx context.Context, requesterId, userId primitive.ObjectID) error {
+	request := FollowRequest{
+		ID:          primitive.NewObjectID(),
+		CreatedAt:   time.Now(),
+		RequesterID: requesterId,
+		UserID:      userId,
+	}
+	filter := bson.M{"requesterId": requesterId, "userId": userId}
+	collection := services.GetMongoDBCollection(config.FollowRequestsCollection)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": request}, options.Update().SetUpsert(true))
+	return err
+}
+
+func DeleteFollowRequest(ctx context.Context, requesterId, userId primitive.ObjectID) (bool, error) {
+	collection := services.GetMongoDBCollection(config.FollowRequestsCollection)
+	result, err := collection.DeleteOne(ctx, bson.M{"requesterId": requesterId, "userId": userId})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount == 1, nil
+}
+
+func HasRequestedToFollow(ctx context.Context, requesterId, userId interface{}) (bool, error) {
+	collection := services.GetMongoDBCollection(config.FollowRequestsCollection)
+	count, err := collection.CountDocuments(ctx, bson.M{"requesterId": requesterId, "userId": userId}, options.Count().SetLimit(1))
+	return count == 1, err
+}
+
+// ApproveFollowRequests should be called within a transaction. It turns every pending request to userId into a follow
+func ApproveFollowRequests(sessCtx mongo.SessionContext, userId primitive.ObjectID) error {
+	collection := services.GetMongoDBCollection(config.FollowRequestsCollection)
+	cursor, err := collection.Find(sessCtx, bson.M{"userId": userId})
+	if err != nil {
+		return err
+	}
+
+	requests := []FollowRequest{}
+	err = cursor.All(sessCtx, &requests)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		requesterId, ok := request.RequesterID.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+
+		_, err = CreateFollow(sessCtx, requesterId, userId)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = collection.DeleteMany(sessCtx, bson.M{"userId": userId})
+	return err
+}
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A Follow is the edge from FollowerID to FolloweeID. The pair is unique
+type Follow struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	FolloweeID primitive.ObjectID `bson:"followeeId" json:"followeeId"`
+	FollowerID primitive.ObjectID `bson:"followerId" json:"followerId"`
+}
+
+func IsFollowing(ctx context.Context, followerId, userId interface{}) (bool, error) {
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	err := followsCollection.FindOne(ctx, bson.M{"followerId": followerId, "followeeId": userId}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateFollow should be called within a transaction. It returns false and does nothing if followerId
+// already follows userId
+func CreateFollow(sessCtx mongo.SessionContext, followerId, userId primitive.ObjectID) (bool, error) {
+	filter := bson.M{"followerId": followerId, "followeeId": userId}
+	update := bson.M{"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "createdAt": time.Now()}}
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	result, err := followsCollection.UpdateOne(sessCtx, filter, update, options.Update().SetUpsert(true))
+	if err != nil || result.UpsertedCount == 0 {
+		return false, err
+	}
+
+	operations := []mongo.WriteModel{
+		&mongo.UpdateOneModel{
+			Filter: bson.M{"_id": userId},
+			Update: bson.M{"$inc": bson.M{"followersCount": 1}},
+		},
+		&mongo.UpdateOneModel{
+			Filter: bson.M{"_id": followerId},
+			Update: bson.M{"$inc": bson.M{"followingCount": 1}},
+		},
+		&mongo.UpdateOneModel{
+			Filter: bson.M{"_id": userId, "followersCount": bson.M{"$gt": config.TimelineFanOutLimit}, "fanOutOnRead": bson.M{"$ne": true}},
+			Update: bson.M{"$set": bson.M{"fanOutOnRead": true}},
+		},
+	}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.BulkWrite(sessCtx, operations)
+	return err == nil, err
+}
+
+// DeleteFollow should be called within a transaction. It also removes the user's posts from the follower's timeline.
+// It does nothing if followerId does not follow userId
+func DeleteFollow(sessCtx mongo.SessionContext, followerId, userId primitive.ObjectID) error {
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	result, err := followsCollection.DeleteOne(sessCtx, bson.M{"followerId": followerId, "followeeId": userId})
+	if err != nil || result.DeletedCount == 0 {
+		return err
+	}
+
+	operations := []mongo.WriteModel{
+		&mongo.UpdateOneModel{
+			Filter: bson.M{"_id": userId},
+			Update: bson.M{"$inc": bson.M{"followersCount": -1}},
+		},
+		&mongo.UpdateOneModel{
+			Filter: bson.M{"_id": followerId},
+			Update: bson.M{"$inc": bson.M{"followingCount": -1}},
+		},
+	}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.BulkWrite(sessCtx, operations)
+	if err != nil {
+		return err
+	}
+
+	timelinesCollection := services.GetMongoDBCollection(config.TimelinesCollection)
+	_, err = timelinesCollection.DeleteMany(sessCtx, bson.M{"userId": followerId, "authorId": userId})
+	return err
+}
+
+// FindFollowerIds returns the ids of the users following userId, newest first
+func FindFollowerIds(ctx context.Context, userId interface{}) (bson.A, error) {
+	return findFollowIds(ctx, bson.M{"followeeId": userId}, "followerId")
+}
+
+func findFollowIds(ctx context.Context, filter bson.M, field string) (bson.A, error) {
+	findOptions := options.Find().SetProjection(bson.M{field: 1}).SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	follows := []Follow{}
+	err = cursor.All(ctx, &follows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := bson.A{}
+	for _, follow := range follows {
+		if field == "followerId" {
+			ids = append(ids, follow.FollowerID)
+		} else {
+			ids = append(ids, follow.FolloweeID)
+		}
+	}
+
+	return ids, nil
+}
+
+// UserListPipeline pages through the edges, such as follows or close friends, matching the filter, newest first,
+// and replaces each edge with the user stored under userField. The position of the edge is kept for pagination.NewPage
+func UserListPipeline(params *pagination.Params, filter bson.M, userField string, projection interface{}) bson.A {
+	match := bson.M{}
+	for key, value := range filter {
+		match[key] = value
+	}
+
+	if len(params.ExcludedIds) > 0 {
+		match[userField] = bson.M{"$nin": params.ExcludedIds}
+	}
+
+	return append(bson.A{bson.M{"$match": match}}, UserListStages(params, userField, projection)...)
+}
+
+// UserListStages pages through the matched follow-like documents and replaces each with the user stored under userField
+func UserListStages(params *pagination.Params, userField string, projection interface{}) bson.A {
+	return append(params.Stages("createdAt", "_id"),
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$" + userField},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": projection},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": "$user"},
+		bson.M{
+			"$replaceRoot": bson.M{
+				"newRoot": bson.M{
+					"$mergeObjects": bson.A{
+						"$user",
+						bson.M{pagination.CursorField: bson.M{"createdAt": "$createdAt", "_id": "$_id"}},
+					},
+				},
+			},
+		},
+	)
+}
+
+type Relationship struct {
+	Blocked    bool `json:"blocked"`
+	FollowedBy bool `json:"followedBy"`
+	Following  bool `json:"following"`
+	Requested  bool `json:"requested"`
+}
+
+// FindRelationship describes how viewerId relates to userId. Blocked means viewerId has blocked userId
+func FindRelationship(ctx context.Context, viewerId, userId primitive.ObjectID) (*Relationship, error) {
+	relationship := &Relationship{}
+	var err error
+	relationship.Following, err = IsFollowing(ctx, viewerId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	relationship.FollowedBy, err = IsFollowing(ctx, userId, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !relationship.Following {
+		relationship.Requested, err = HasRequestedToFollow(ctx, viewerId, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	relationship.Blocked, err = HasBlocked(ctx, viewerId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return relationship, nil
+}
+
+// MutualFollowersStages match the follows of the followers of userId that viewerId also follows, leaving out deactivated
+// users and users that have a block with viewerId. They run on the follows collection and start from the follows of
+// viewerId, so that only the accounts viewerId follows are looked up
+func MutualFollowersStages(ctx context.Context, viewerId, userId primitive.ObjectID) (bson.A, error) {
+	hiddenIds, err := FindHiddenUserIds(ctx, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.A{
+		bson.M{"$match": bson.M{"followerId": viewerId, "followeeId": bson.M{"$nin": hiddenIds}}},
+		FollowerLookupStage("followeeId", userId, "mutualFollow"),
+		bson.M{"$unwind": "$mutualFollow"},
+		bson.M{"$replaceRoot": bson.M{"newRoot": "$mutualFollow"}},
+	}, nil
+}
+
+// FollowingLookupStage looks up, under as, the follow of followerId to the user whose id is stored under followeeField.
+// as is empty when followerId does not follow that user
+func FollowingLookupStage(followerId interface{}, followeeField, as string) bson.M {
+	return followLookupStage(bson.M{"followerId": followerId}, "followeeId", followeeField, as)
+}
+
+// FollowerLookupStage looks up, under as, the follow to followeeId of the user whose id is stored under followerField
+func FollowerLookupStage(followerField string, followeeId interface{}, as string) bson.M {
+	return followLookupStage(bson.M{"followeeId": followeeId}, "followerId", followerField, as)
+}
+
+func followLookupStage(filter bson.M, followField, localField, as string) bson.M {
+	match := bson.M{"$expr": bson.M{"$eq": bson.A{"$" + followField, "$$userId"}}}
+	for key, value := range filter {
+		match[key] = value
+	}
+
+	return bson.M{
+		"$lookup": bson.M{
+			"from":     config.FollowsCollection,
+			"let":      bson.M{"userId": "$" + localField},
+			"pipeline": bson.A{bson.M{"$match": match}},
+			"as":       as,
+		},
+	}
+}
+
+// FollowedBy backs "Followed by alice, bob and 12 others you follow". Count includes the previewed Users
+type FollowedBy struct {
+	Count int64    `json:"count"`
+	Users []bson.M `json:"users"`
+}
+
+func FindFollowedBy(ctx context.Context, viewerId, userId primitive.ObjectID) (*FollowedBy, error) {
+	followedBy := &FollowedBy{Users: []bson.M{}}
+	stages, err := MutualFollowersStages(ctx, viewerId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	followsCollection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := followsCollection.Aggregate(ctx, append(stages, bson.M{"$count": "count"}))
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []struct {
+		Count int64 `bson:"count"`
+	}{}
+	err = cursor.All(ctx, &counts)
+	if err != nil || len(counts) == 0 {
+		return followedBy, err
+	}
+
+	followedBy.Count = counts[0].Count
+	params := &pagination.Params{Limit: config.FollowedByPreviewLength}
+	projection := bson.M{"username": 1, "image": 1, "name": 1}
+	cursor, err = followsCollection.Aggregate(ctx, append(stages, UserListStages(params, "followerId", projection)...))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &followedBy.Users)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := pagination.NewPage(followedBy.Users, params, "createdAt", "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	followedBy.Users = page.Items
+	return followedBy, nil
+}
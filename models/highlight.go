@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Highlights embed copies of archived stories so they outlive the 24 hour expiry
+type Highlight struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	Stories   []bson.M           `bson:"stories" json:"stories"`
+	StoryIDs  []string           `bson:"-" json:"storyIds,omitempty" binding:"required,min=1,max=100,dive,object_id"`
+	Title     string             `bson:"title" json:"title" binding:"required,max=15"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId,omitempty"`
+}
+
+func (highlight *Highlight) NormalizeFields(userId primitive.ObjectID) {
+	highlight.ID = primitive.NewObjectID()
+	highlight.CreatedAt = time.Now()
+	highlight.Title = strings.TrimSpace(highlight.Title)
+	highlight.UserID = userId
+}
+
+func (highlight *Highlight) GetStoryIds() []primitive.ObjectID {
+	storyIds := []primitive.ObjectID{}
+	for _, value := range highlight.StoryIDs {
+		storyId, err := primitive.ObjectIDFromHex(value)
+		if err == nil {
+			storyIds = append(storyIds, storyId)
+		}
+	}
+
+	return storyIds
+}
+
+func (highlight *Highlight) SetStories(stories []Story) {
+	highlight.StoryIDs = nil
+	highlight.Stories = []bson.M{}
+	for _, story := range stories {
+		highlight.Stories = append(highlight.Stories, story.HighlightItem())
+	}
+}
+
+type FindHighlightResult struct {
+	Error        error
+	Highlight    *Highlight
+	ResponseBody interface{}
+	StatusCode   int
+}
+
+func FindHighlight(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindHighlightResult {
+	highlight := &Highlight{}
+	collection := services.GetMongoDBCollection(config.HighlightsCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(highlight)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindHighlightResult{
+			ResponseBody: gin.H{"message": "Highlight not found"},
+			StatusCode:   http.StatusNotFound,
+			Error:        err,
+		}
+	}
+
+	if err != nil {
+		return &FindHighlightResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
+		}
+	}
+
+	return &FindHighlightResult{
+		Highlight: highlight,
+	}
+}
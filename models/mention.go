@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mention locates an @username in a message. Offset and Length are counted in UTF-16 code units, like
+// JavaScript string indices, and include the "@" so clients can link the text even after the username changes.
+type Mention struct {
+	Length int                `bson:"length" json:"length"`
+	Offset int                `bson:"offset" json:"offset"`
+	UserID primitive.ObjectID `bson:"userId" json:"userId"`
+}
+
+// FindMentions resolves the @usernames in the text to users with a single query. Unknown usernames are skipped.
+func FindMentions(ctx context.Context, text string) ([]Mention, error) {
+	type match struct {
+		end      int
+		start    int
+		username string
+	}
+
+	matches := []match{}
+	usernames := bson.A{}
+	for _, indexes := range tagRegex.FindAllStringSubmatchIndex(text, -1) {
+		// Usernames cannot end with a period, so a trailing one ends the sentence
+		username := strings.TrimRight(text[indexes[2]:indexes[3]], ".")
+		if username == "" {
+			continue
+		}
+
+		matches = append(matches, match{start: indexes[0], end: indexes[2] + len(username), username: username})
+		usernames = append(usernames, username)
+	}
+
+	mentions := []Mention{}
+	if len(matches) == 0 {
+		return mentions, nil
+	}
+
+	findOptions := options.Find().SetProjection(bson.M{"username": 1})
+	collection := services.GetMongoDBCollection(config.UsersCollection)
+	cursor, err := collection.Find(ctx, bson.M{"username": bson.M{"$in": usernames}}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []User{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := map[string]primitive.ObjectID{}
+	for _, user := range users {
+		userIds[user.Username] = user.ID
+	}
+
+	for _, match := range matches {
+		userId, ok := userIds[match.username]
+		if !ok {
+			continue
+		}
+
+		offset := utf16Length(text[:match.start])
+		mentions = append(mentions, Mention{
+			Length: utf16Length(text[match.start:match.end]),
+			Offset: offset,
+			UserID: userId,
+		})
+	}
+
+	return mentions, nil
+}
+
+// GetMentionedUserIds returns the distinct users of the mentions that are not mentioned in previous
+func GetMentionedUserIds(mentions []Mention, previous []Mention) bson.A {
+	seen := map[primitive.ObjectID]bool{}
+	for _, mention := range previous {
+		seen[mention.UserID] = true
+	}
+
+	userIds := bson.A{}
+	for _, mention := range mentions {
+		if !seen[mention.UserID] {
+			seen[mention.UserID] = true
+			userIds = append(userIds, mention.UserID)
+		}
+	}
+
+	return userIds
+}
+
+func utf16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
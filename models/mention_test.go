@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUTF16Length(t *testing.T) {
+	assert.Equal(t, 5, utf16Length("hello"))
+	assert.Equal(t, 2, utf16Length("😀"))
+	assert.Equal(t, 1, utf16Length("é"))
+}
+
+func TestGetMentionedUserIds(t *testing.T) {
+	alice := primitive.NewObjectID()
+	bob := primitive.NewObjectID()
+	mentions := []Mention{{UserID: alice}, {UserID: bob}, {UserID: alice}}
+
+	assert.Equal(t, bson.A{alice, bob}, GetMentionedUserIds(mentions, nil))
+	assert.Equal(t, bson.A{bob}, GetMentionedUserIds(mentions, []Mention{{UserID: alice}}))
+}
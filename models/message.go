@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Message struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"_id,omitempty"`
+	ConversationID primitive.ObjectID   `bson:"conversationId" json:"conversationId"`
+	CreatedAt      time.Time            `bson:"createdAt" json:"createdAt"`
+	DeletedFor     []primitive.ObjectID `bson:"deletedFor,omitempty" json:"-"`
+	IsDeleted      bool                 `bson:"isDeleted" json:"isDeleted"`
+	PostID         interface{}          `bson:"postId,omitempty" json:"postId,omitempty" binding:"omitempty,object_id"`
+	Text           string               `bson:"text,omitempty" json:"text,omitempty" binding:"max=1000"`
+	UserID         primitive.ObjectID   `bson:"userId" json:"userId"`
+}
+
+func (message *Message) NormalizeFields(conversationId, userId primitive.ObjectID) error {
+	message.ID = primitive.NewObjectID()
+	message.ConversationID = conversationId
+	message.CreatedAt = time.Now()
+	message.DeletedFor = nil
+	message.IsDeleted = false
+	message.Text = strings.TrimSpace(message.Text)
+	message.UserID = userId
+
+	if message.PostID == nil || message.PostID == "" {
+		message.PostID = nil
+		return nil
+	}
+
+	postId, err := primitive.ObjectIDFromHex(fmt.Sprintf("%v", message.PostID))
+	message.PostID = postId
+	return err
+}
+
+// Preview is stored on the conversation so GET /conversations does not need to look up messages
+func (message *Message) Preview() bson.M {
+	return bson.M{
+		"_id":       message.ID,
+		"createdAt": message.CreatedAt,
+		"isDeleted": message.IsDeleted,
+		"postId":    message.PostID,
+		"text":      message.Text,
+		"userId":    message.UserID,
+	}
+}
+
+type FindMessageResult struct {
+	Error        error
+	Message      *Message
+	ResponseBody interface{}
+	StatusCode   int
+}
+
+func FindMessage(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindMessageResult {
+	message := &Message{}
+	collection := services.GetMongoDBCollection(config.MessagesCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindMessageResult{
+			ResponseBody: gin.H{"message": "Message not found"},
+			StatusCode:   http.StatusNotFound,
+			Error:        err,
+		}
+	}
+
+	if err != nil {
+		return &FindMessageResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
+		}
+	}
+
+	return &FindMessageResult{
+		Message: message,
+	}
+}
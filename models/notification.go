@@ -0,0 +1,141 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CommentNotification       = "comment"
+	DataExportNotification    = "data_export"
+	FollowNotification        = "follow"
+	FollowRequestNotification = "follow_request"
+	MentionNotification       = "mention"
+	ReplyNotification         = "reply"
+	TagNotification           = "tag"
+)
+
+// Unread notifications of the same type about the same entity are grouped, e.g "alice and 5 others commented on your post".
+// Notifications without actors, such as those of data exports, may carry a URL that stops working at ExpiresAt
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	ActorIDs  []interface{}      `bson:"actorIds" json:"actorIds,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	EntityID  interface{}        `bson:"entityId" json:"entityId"`
+	ExpiresAt *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	Read      bool               `bson:"read" json:"read"`
+	Type      string             `bson:"type" json:"type"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	URL       string             `bson:"url,omitempty" json:"url,omitempty"`
+	UserID    interface{}        `bson:"userId" json:"userId,omitempty"`
+}
+
+type NotificationSettings struct {
+	MutedNotifications []string `bson:"mutedNotifications" json:"mutedNotifications" binding:"required,dive,oneof=comment follow follow_request mention reply tag"`
+}
+
+// CreateNotification adds the actor to the unread group of the notification unless the user has muted its type,
+// the actor is the user or either of them has blocked the other. Follows and follow requests notify the user once
+// per actor, so following and unfollowing over and over does not flood them. Notifications are sent after the
+// write they are about succeeded, so handlers log their errors instead of failing the request
+func CreateNotification(ctx context.Context, userId, actorId interface{}, notificationType string, entityId interface{}) error {
+	if userId == actorId {
+		return nil
+	}
+
+	filter := bson.M{"_id": userId, "mutedNotifications": bson.M{"$ne": notificationType}}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	count, err := usersCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil || count == 0 {
+		return err
+	}
+
+	blocked, err := IsBlocked(ctx, userId, actorId)
+	if err != nil || blocked {
+		return err
+	}
+
+	if notificationType == FollowNotification || notificationType == FollowRequestNotification {
+		filter = bson.M{"userId": userId, "type": notificationType, "entityId": entityId, "actorIds": actorId}
+		notificationsCollection := services.GetMongoDBCollection(config.NotificationsCollection)
+		count, err = notificationsCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		if err != nil || count == 1 {
+			return err
+		}
+	}
+
+	now := time.Now()
+	filter = bson.M{"userId": userId, "type": notificationType, "entityId": entityId, "read": false}
+	update := bson.M{
+		"$addToSet":    bson.M{"actorIds": actorId},
+		"$set":         bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "createdAt": now},
+	}
+	notificationsCollection := services.GetMongoDBCollection(config.NotificationsCollection)
+	_, err = notificationsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func CreateNotifications(ctx context.Context, userIds bson.A, actorId interface{}, notificationType string, entityId interface{}) error {
+	for _, userId := range userIds {
+		err := CreateNotification(ctx, userId, actorId, notificationType, entityId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NotificationStages replaces actorIds with the latest actors and their total count
+func NotificationStages() bson.A {
+	return bson.A{
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"actorIds": bson.M{"$slice": bson.A{"$actorIds", -3}}},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$actorIds"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1}},
+				},
+				"as": "actors",
+			},
+		},
+		bson.M{"$addFields": bson.M{"actorsCount": bson.M{"$size": "$actorIds"}}},
+		bson.M{"$project": bson.M{"actorIds": 0, "userId": 0}},
+	}
+}
+
+// FindTaggedUserIds returns the ids of the users tagged with @username in the text
+func FindTaggedUserIds(ctx context.Context, text string) (bson.A, error) {
+	ids := bson.A{}
+	usernames := findTaggedUsernames(text)
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1})
+	collection := services.GetMongoDBCollection(config.UsersCollection)
+	cursor, err := collection.Find(ctx, bson.M{"username": bson.M{"$in": usernames}}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []User{}
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+
+	return ids, nil
+}
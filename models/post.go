@@ -17,23 +17,46 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	EveryoneCommentsAudience  = "everyone"
+	FollowersCommentsAudience = "followers"
+)
+
+const (
+	CloseFriendsPostAudience = "close_friends"
+	FollowersPostAudience    = "followers"
+	PublicPostAudience       = "public"
+)
+
 var (
-	PostProjection = bson.M{"images": 1, "likesCount": 1, "commentsCount": 1, "createdAt": 1, "repliesCount": 1}
+	PostProjection = bson.M{"audience": 1, "images": 1, "likesCount": 1, "commentsCount": 1, "createdAt": 1, "repliesCount": 1}
 )
 
 type Post struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty" `
-	Caption       string             `bson:"caption" json:"caption"`
-	Comments      []Comment          `bson:"comments" json:"comments"`
-	CommentsCount int                `bson:"commentsCount" json:"commentsCount"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	Images        []string           `bson:"images" json:"images"`
-	ImageCount    int                `bson:"imageCount,omitempty" json:"imageCount,omitempty" binding:"gt=0"`
-	LikesCount    int                `bson:"likesCount" json:"likesCount"`
-	Location      string             `bson:"location" json:"location"`
-	RepliesCount  int                `bson:"repliesCount" json:"repliesCount"`
-	User          bson.M             `bson:"user,omitempty" json:"user"`
-	UserID        interface{}        `bson:"userId,omitempty" json:"userId,omitempty"`
+	ID               primitive.ObjectID   `bson:"_id,omitempty" json:"_id,omitempty" `
+	ArchivedAt       *time.Time           `bson:"archivedAt,omitempty" json:"archivedAt,omitempty"`
+	Audience         string               `bson:"audience,omitempty" json:"audience,omitempty" binding:"omitempty,oneof=public followers close_friends"`
+	Caption          string               `bson:"caption" json:"caption"`
+	Comments         []Comment            `bson:"comments" json:"comments"`
+	CommentsAudience string               `bson:"commentsAudience,omitempty" json:"commentsAudience,omitempty" binding:"omitempty,oneof=everyone followers"`
+	CommentsCount    int                  `bson:"commentsCount" json:"commentsCount"`
+	CommentsDisabled bool                 `bson:"commentsDisabled" json:"commentsDisabled"`
+	CreatedAt        time.Time            `bson:"createdAt" json:"createdAt"`
+	DeletedAt        *time.Time           `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy        interface{}          `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	Images           []string             `bson:"images" json:"images"`
+	ImageCount       int                  `bson:"imageCount,omitempty" json:"imageCount,omitempty" binding:"gt=0"`
+	LikesCount       int                  `bson:"likesCount" json:"likesCount"`
+	Location         string               `bson:"location" json:"location"`
+	PinnedCommentIDs []primitive.ObjectID `bson:"pinnedCommentIds,omitempty" json:"pinnedCommentIds,omitempty"`
+	RepliesCount     int                  `bson:"repliesCount" json:"repliesCount"`
+	User             bson.M               `bson:"user,omitempty" json:"user"`
+	UserID           interface{}          `bson:"userId,omitempty" json:"userId,omitempty"`
+}
+
+// IsHiddenFrom reports whether the post has been taken out of circulation, e.g archived, for anyone but its author
+func (post *Post) IsHiddenFrom(viewerId interface{}) bool {
+	return post.ArchivedAt != nil && post.UserID != viewerId
 }
 
 func (post *Post) GeneratePresignedURLKeys() []string {
@@ -46,20 +69,53 @@ func (post *Post) GeneratePresignedURLKeys() []string {
 	return keys
 }
 
-func (post *Post) GetCommentIds() bson.A {
-	commentIds := bson.A{}
+func (post *Post) IsCommentPinned(commentId primitive.ObjectID) bool {
+	for _, pinnedCommentId := range post.PinnedCommentIDs {
+		if pinnedCommentId == commentId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveCommentsByUsers drops the embedded comments written by any of the users
+func (post *Post) RemoveCommentsByUsers(userIds bson.A) {
+	comments := []Comment{}
 	for _, comment := range post.Comments {
-		commentIds = append(commentIds, comment.ID)
+		excluded := false
+		for _, userId := range userIds {
+			if comment.UserID == userId {
+				excluded = true
+				break
+			}
+		}
+
+		if !excluded {
+			comments = append(comments, comment)
+		}
 	}
 
-	return commentIds
+	post.Comments = comments
 }
 
 func (post *Post) NormalizeFields(userId interface{}) {
 	post.ID = primitive.NewObjectID()
+	post.ArchivedAt = nil
+	post.DeletedAt = nil
+	post.DeletedBy = nil
 	post.CreatedAt = time.Now()
+	post.PinnedCommentIDs = nil
 	post.UserID = userId
 
+	if post.Audience == "" {
+		post.Audience = PublicPostAudience
+	}
+
+	if post.CommentsAudience == "" {
+		post.CommentsAudience = EveryoneCommentsAudience
+	}
+
 	if post.Comments == nil {
 		post.Comments = []Comment{}
 	}
@@ -117,6 +173,7 @@ func MapPostsToUserSubDocuments(posts ...Post) []bson.M {
 	for index, post := range posts {
 		postDocument := bson.M{
 			"_id":           post.ID,
+			"audience":      post.Audience,
 			"images":        post.Images,
 			"likesCount":    post.LikesCount,
 			"commentsCount": post.CommentsCount,
@@ -128,3 +185,117 @@ func MapPostsToUserSubDocuments(posts ...Post) []bson.M {
 
 	return postDocuments
 }
+
+// ExcludeHiddenPosts adds the conditions that leave out posts which should not appear in listings, i.e archived and
+// soft deleted posts, to the filter
+func ExcludeHiddenPosts(filter bson.M) bson.M {
+	filter["archivedAt"] = nil
+	return ExcludeDeleted(filter)
+}
+
+// RemovePostFromUser should be called within a transaction. It drops the post from the user's embedded preview,
+// refills the preview with the next most recent post and decrements the user's postsCount. The user must be
+// loaded with its posts and postsCount
+func RemovePostFromUser(sessCtx mongo.SessionContext, user *User, postId primitive.ObjectID) error {
+	update := bson.M{
+		"$pull": bson.M{"posts": bson.M{"_id": postId}},
+		"$inc":  bson.M{"postsCount": -1},
+	}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.UpdateByID(sessCtx, user.ID, update)
+	if err != nil {
+		return err
+	}
+
+	if user.PostsCount <= config.CommonPaginationLength {
+		return nil
+	}
+
+	recentPost := bson.M{}
+	postIds := append(user.GetPostIds(), postId)
+	filter := ExcludeHiddenPosts(bson.M{"_id": bson.M{"$nin": postIds}, "userId": user.ID})
+	findOneOptions := options.FindOne().SetProjection(PostProjection).SetSort(bson.M{"createdAt": -1})
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	err = postsCollection.FindOne(sessCtx, filter, findOneOptions).Decode(&recentPost)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = usersCollection.UpdateByID(sessCtx, user.ID, bson.M{"$push": bson.M{"posts": recentPost}})
+	return err
+}
+
+// AddPostToUser should be called within a transaction. It is the reverse of RemovePostFromUser and puts the post
+// back at its place in the user's embedded preview
+func AddPostToUser(sessCtx mongo.SessionContext, post Post) error {
+	update := bson.M{
+		"$push": bson.M{
+			"posts": bson.M{
+				"$each":  MapPostsToUserSubDocuments(post),
+				"$sort":  bson.M{"createdAt": -1},
+				"$slice": config.CommonPaginationLength,
+			},
+		},
+		"$inc": bson.M{"postsCount": 1},
+	}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.UpdateByID(sessCtx, post.UserID, update)
+	return err
+}
+
+// ArchivePost should be called within a transaction. The post keeps its comments, replies and likes but leaves the
+// author's preview, postsCount and every timeline. It returns false if the post was already archived
+func ArchivePost(sessCtx mongo.SessionContext, user *User, postId primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": postId, "archivedAt": nil}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	result, err := postsCollection.UpdateOne(sessCtx, filter, bson.M{"$set": bson.M{"archivedAt": time.Now()}})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	timelinesCollection := services.GetMongoDBCollection(config.TimelinesCollection)
+	_, err = timelinesCollection.DeleteMany(sessCtx, bson.M{"postId": postId})
+	if err != nil {
+		return false, err
+	}
+
+	return true, RemovePostFromUser(sessCtx, user, postId)
+}
+
+// UnarchivePost should be called within a transaction. It returns false if the post was not archived
+func UnarchivePost(sessCtx mongo.SessionContext, user *User, post *Post) (bool, error) {
+	filter := bson.M{"_id": post.ID, "archivedAt": bson.M{"$ne": nil}}
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	result, err := postsCollection.UpdateOne(sessCtx, filter, bson.M{"$unset": bson.M{"archivedAt": ""}})
+	if err != nil || result.ModifiedCount == 0 {
+		return false, err
+	}
+
+	post.ArchivedAt = nil
+	return true, republishPost(sessCtx, user, post)
+}
+
+// republishPost puts a post that was taken out of circulation back in the author's preview and postsCount, and in
+// the timelines of the author and, unless its posts are fanned out on read, of its followers
+func republishPost(sessCtx mongo.SessionContext, user *User, post *Post) error {
+	err := AddPostToUser(sessCtx, *post)
+	if err != nil {
+		return err
+	}
+
+	timelineUserIds := bson.A{user.ID}
+	if !IsFanOutOnRead(user) {
+		followerIds, err := FindFollowerIds(sessCtx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		timelineUserIds = append(timelineUserIds, followerIds...)
+	}
+
+	return AddPostsToTimelines(sessCtx, timelineUserIds, *post)
+}
@@ -1,27 +1,54 @@
 package models
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Replies are rooted at a comment. A reply to another reply keeps the comment as ReplyToID and
+// references the reply as ParentReplyID. Replies deleted along with their comment reference it as DeletedWithID.
 type Reply struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty" `
-	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
-	LikesCount int                `bson:"likesCount" json:"likesCount"`
-	Message    string             `bson:"message" json:"message" binding:"required"`
-	PostID     interface{}        `bson:"postId" json:"postId" binding:"object_id"`
-	ReplyToID  interface{}        `bson:"replyToId" json:"replyToId" binding:"object_id"`
-	User       bson.M             `bson:"user,omitempty" json:"user,omitempty"`
-	UserID     interface{}        `bson:"userId,omitempty" json:"userId,omitempty"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty" `
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	DeletedAt     *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy     interface{}        `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	DeletedWithID interface{}        `bson:"deletedWithId,omitempty" json:"deletedWithId,omitempty"`
+	EditedAt      *time.Time         `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
+	Edits         []CommentEdit      `bson:"edits,omitempty" json:"edits,omitempty"`
+	IsHidden      bool               `bson:"isHidden,omitempty" json:"isHidden,omitempty"`
+	LikesCount    int                `bson:"likesCount" json:"likesCount"`
+	Mentions      []Mention          `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	Message       string             `bson:"message" json:"message" binding:"required"`
+	ParentReplyID interface{}        `bson:"parentReplyId,omitempty" json:"parentReplyId,omitempty" binding:"omitempty,object_id"`
+	PostID        interface{}        `bson:"postId" json:"postId" binding:"object_id"`
+	ReplyToID     interface{}        `bson:"replyToId" json:"replyToId" binding:"object_id"`
+	ReplyToUser   bson.M             `bson:"-" json:"replyToUser,omitempty"`
+	ReplyToUserID interface{}        `bson:"replyToUserId,omitempty" json:"replyToUserId,omitempty"`
+	User          bson.M             `bson:"user,omitempty" json:"user,omitempty"`
+	UserID        interface{}        `bson:"userId,omitempty" json:"userId,omitempty"`
 }
 
 func (reply *Reply) NormalizeFields(userId primitive.ObjectID) error {
 	reply.ID = primitive.NewObjectID()
 	reply.CreatedAt = time.Now()
+	reply.DeletedAt = nil
+	reply.DeletedBy = nil
+	reply.DeletedWithID = nil
+	reply.EditedAt = nil
+	reply.Edits = nil
+	reply.IsHidden = false
+	reply.Mentions = nil
 	reply.UserID = userId
 
 	var err error
@@ -35,7 +62,23 @@ func (reply *Reply) NormalizeFields(userId primitive.ObjectID) error {
 		return err
 	}
 
-	return nil
+	reply.ReplyToUserID = nil
+	if reply.ParentReplyID == nil || reply.ParentReplyID == "" {
+		reply.ParentReplyID = nil
+		return nil
+	}
+
+	reply.ParentReplyID, err = primitive.ObjectIDFromHex(fmt.Sprintf("%v", reply.ParentReplyID))
+	return err
+}
+
+func (reply *Reply) Edit(message string) {
+	reply.Edits, reply.EditedAt = appendEdit(reply.Edits, reply.EditedAt, reply.CreatedAt, reply.Message)
+	reply.Message = message
+}
+
+func (reply *Reply) IsEditable() bool {
+	return time.Since(reply.CreatedAt) <= config.CommentEditWindow
 }
 
 func (reply *Reply) SetUser(user *User) {
@@ -45,3 +88,73 @@ func (reply *Reply) SetUser(user *User) {
 		"image":    user.Image,
 	}
 }
+
+func (reply *Reply) SetReplyToUser(user *User) {
+	reply.ReplyToUser = bson.M{
+		"username": user.Username,
+		"image":    user.Image,
+	}
+}
+
+// ReplyUserStages replaces userId with the author and adds the user being replied to as replyToUser
+func ReplyUserStages() bson.A {
+	return bson.A{
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"image": 1, "username": 1, "_id": 0}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$user"}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$replyToUserId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"image": 1, "username": 1}},
+				},
+				"as": "replyToUser",
+			},
+		},
+		bson.M{"$unwind": bson.M{"path": "$replyToUser", "preserveNullAndEmptyArrays": true}},
+		bson.M{"$project": bson.M{"userId": 0}},
+	}
+}
+
+type FindReplyResult struct {
+	Error        error
+	Reply        *Reply
+	ResponseBody interface{}
+	StatusCode   int
+}
+
+func FindReply(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindReplyResult {
+	reply := &Reply{}
+	collection := services.GetMongoDBCollection(config.RepliesCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(reply)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindReplyResult{
+			ResponseBody: gin.H{"message": "Reply not found"},
+			StatusCode:   http.StatusNotFound,
+			Error:        err,
+		}
+	}
+
+	if err != nil {
+		return &FindReplyResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
+		}
+	}
+
+	return &FindReplyResult{
+		Reply: reply,
+	}
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SavedCollection struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	Name       string             `bson:"name" json:"name" binding:"required,max=50"`
+	PostsCount int                `bson:"postsCount" json:"postsCount"`
+	UserID     interface{}        `bson:"userId,omitempty" json:"userId,omitempty"`
+}
+
+func (collection *SavedCollection) NormalizeFields(userId primitive.ObjectID) {
+	collection.ID = primitive.NewObjectID()
+	collection.CreatedAt = time.Now()
+	collection.Name = strings.TrimSpace(collection.Name)
+	collection.PostsCount = 0
+	collection.UserID = userId
+}
+
+type FindSavedCollectionResult struct {
+	SavedCollection *SavedCollection
+	ResponseBody    interface{}
+	StatusCode      int
+}
+
+func FindSavedCollection(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindSavedCollectionResult {
+	savedCollection := &SavedCollection{}
+	collection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(savedCollection)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindSavedCollectionResult{
+			ResponseBody: gin.H{"message": "Collection not found"},
+			StatusCode:   http.StatusNotFound,
+		}
+	}
+
+	if err != nil {
+		return &FindSavedCollectionResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+		}
+	}
+
+	return &FindSavedCollectionResult{
+		SavedCollection: savedCollection,
+	}
+}
+
+// SavePost pushes the post into the user's saved posts buckets unless it is already there. A post saved
+// concurrently into another bucket is rejected by the unique index on the saved posts of the user
+func SavePost(ctx context.Context, userId, postId primitive.ObjectID) error {
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	err := userDetailsCollection.FindOne(ctx, bson.M{"userId": userId, "savedPosts": postId}).Err()
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	filter := bson.M{
+		"savedPosts":      bson.M{"$ne": postId},
+		"savedPostsCount": bson.M{"$lt": config.LargePaginationLength},
+		"userId":          userId,
+	}
+	update := bson.M{
+		"$push":        bson.M{"savedPosts": bson.M{"$each": bson.A{postId}, "$position": 0}},
+		"$inc":         bson.M{"savedPostsCount": 1},
+		"$setOnInsert": NewUserDetails(bson.A{"savedPosts", "savedPostsCount"}),
+	}
+	_, err = userDetailsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+// UnsavePost removes the post from the user's saved posts and from every one of the user's collections
+func UnsavePost(ctx context.Context, userId, postId primitive.ObjectID) error {
+	filter := bson.M{"userId": userId, "savedPosts": postId}
+	update := bson.M{"$pull": bson.M{"savedPosts": postId}, "$inc": bson.M{"savedPostsCount": -1}}
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	_, err := userDetailsCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	filter = bson.M{"userId": userId, "posts": postId}
+	savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	collectionIds, err := savedCollectionPostsCollection.Distinct(ctx, "collectionId", filter)
+	if err != nil {
+		return err
+	}
+
+	if len(collectionIds) == 0 {
+		return nil
+	}
+
+	update = bson.M{"$pull": bson.M{"posts": postId}, "$inc": bson.M{"postsCount": -1}}
+	_, err = savedCollectionPostsCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	filter = bson.M{"_id": bson.M{"$in": collectionIds}}
+	update = bson.M{"$inc": bson.M{"postsCount": -1}}
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// AddPostToSavedCollection saves the post and pushes it into the collection's buckets unless it is already there
+func AddPostToSavedCollection(ctx context.Context, userId, collectionId, postId primitive.ObjectID) error {
+	err := SavePost(ctx, userId, postId)
+	if err != nil {
+		return err
+	}
+
+	savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	err = savedCollectionPostsCollection.FindOne(ctx, bson.M{"collectionId": collectionId, "posts": postId}).Err()
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	filter := bson.M{
+		"collectionId": collectionId,
+		"posts":        bson.M{"$ne": postId},
+		"postsCount":   bson.M{"$lt": config.LargePaginationLength},
+		"userId":       userId,
+	}
+	update := bson.M{
+		"$push":        bson.M{"posts": bson.M{"$each": bson.A{postId}, "$position": 0}},
+		"$inc":         bson.M{"postsCount": 1},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "createdAt": time.Now()},
+	}
+	_, err = savedCollectionPostsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.UpdateByID(ctx, collectionId, bson.M{"$inc": bson.M{"postsCount": 1}})
+	return err
+}
+
+func RemovePostFromSavedCollection(ctx context.Context, collectionId, postId primitive.ObjectID) error {
+	filter := bson.M{"collectionId": collectionId, "posts": postId}
+	update := bson.M{"$pull": bson.M{"posts": postId}, "$inc": bson.M{"postsCount": -1}}
+	savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	result, err := savedCollectionPostsCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+
+	savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.UpdateByID(ctx, collectionId, bson.M{"$inc": bson.M{"postsCount": -result.ModifiedCount}})
+	return err
+}
+
+// RemoveSavedPosts should be called within a transaction. It removes the posts from the saved posts and the
+// collections of every user, such as when the posts are permanently deleted
+func RemoveSavedPosts(sessCtx mongo.SessionContext, postIds bson.A) error {
+	filter := bson.M{"posts": bson.M{"$in": postIds}}
+	removedPostsStage := bson.M{"$size": bson.M{"$setIntersection": bson.A{"$posts", postIds}}}
+	pipeline := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{"_id": "$collectionId", "removed": bson.M{"$sum": removedPostsStage}}},
+	}
+	savedCollectionPostsCollection := services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	cursor, err := savedCollectionPostsCollection.Aggregate(sessCtx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	results := []struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Removed int                `bson:"removed"`
+	}{}
+	err = cursor.All(sessCtx, &results)
+	if err != nil {
+		return err
+	}
+
+	if len(results) > 0 {
+		operations := []mongo.WriteModel{}
+		for _, result := range results {
+			operations = append(operations, &mongo.UpdateOneModel{
+				Filter: bson.M{"_id": result.ID},
+				Update: bson.M{"$inc": bson.M{"postsCount": -result.Removed}},
+			})
+		}
+
+		savedCollectionsCollection := services.GetMongoDBCollection(config.SavedCollectionsCollection)
+		_, err = savedCollectionsCollection.BulkWrite(sessCtx, operations)
+		if err != nil {
+			return err
+		}
+
+		_, err = savedCollectionPostsCollection.UpdateMany(sessCtx, filter, removeFromBucketStages("posts", "postsCount", postIds))
+		if err != nil {
+			return err
+		}
+	}
+
+	filter = bson.M{"savedPosts": bson.M{"$in": postIds}}
+	userDetailsCollection := services.GetMongoDBCollection(config.UserDetailsCollection)
+	_, err = userDetailsCollection.UpdateMany(sessCtx, filter, removeFromBucketStages("savedPosts", "savedPostsCount", postIds))
+	return err
+}
+
+// removeFromBucketStages is an update pipeline that removes ids from the bucket's array and recounts it
+func removeFromBucketStages(field, countField string, ids bson.A) bson.A {
+	remaining := bson.M{
+		"$filter": bson.M{
+			"input": "$" + field,
+			"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$this", ids}}}},
+		},
+	}
+
+	return bson.A{
+		bson.M{"$set": bson.M{field: remaining}},
+		bson.M{"$set": bson.M{countField: bson.M{"$size": "$" + field}}},
+	}
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Stories are removed by a TTL index on expiresAt. A copy is kept in the owner's story archive,
+// from which highlights are created.
+type Story struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	ExpiresAt    time.Time          `bson:"expiresAt" json:"expiresAt"`
+	Image        string             `bson:"image" json:"image"`
+	UserID       primitive.ObjectID `bson:"userId" json:"userId,omitempty"`
+	ViewersCount int                `bson:"viewersCount" json:"viewersCount"`
+}
+
+func (story *Story) NormalizeFields(userId primitive.ObjectID) {
+	story.ID = primitive.NewObjectID()
+	story.CreatedAt = time.Now()
+	story.ExpiresAt = story.CreatedAt.Add(config.StoryTTLInSeconds * time.Second)
+	story.UserID = userId
+	story.ViewersCount = 0
+}
+
+func (story *Story) GeneratePresignedURLKey() string {
+	return "stories/" + story.ID.Hex()
+}
+
+func (story *Story) SetImage(url string) {
+	story.Image = strings.Split(url, "?")[0]
+}
+
+// HighlightItem is the copy of an archived story stored in a highlight
+func (story *Story) HighlightItem() bson.M {
+	return bson.M{"_id": story.ID, "createdAt": story.CreatedAt, "image": story.Image}
+}
+
+type FindStoryResult struct {
+	Error        error
+	ResponseBody interface{}
+	StatusCode   int
+	Story        *Story
+}
+
+func FindStory(ctx context.Context, filter interface{}, options ...*options.FindOneOptions) *FindStoryResult {
+	story := &Story{}
+	collection := services.GetMongoDBCollection(config.StoriesCollection)
+	err := collection.FindOne(ctx, filter, options...).Decode(story)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &FindStoryResult{
+			ResponseBody: gin.H{"message": "Story not found"},
+			StatusCode:   http.StatusNotFound,
+			Error:        err,
+		}
+	}
+
+	if err != nil {
+		return &FindStoryResult{
+			ResponseBody: gin.H{"message": err.Error()},
+			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
+		}
+	}
+
+	return &FindStoryResult{
+		Story: story,
+	}
+}
+
+// RecordStoryView counts each viewer once. Views expire together with the story.
+func RecordStoryView(ctx context.Context, story *Story, viewerId primitive.ObjectID) error {
+	filter := bson.M{"storyId": story.ID, "viewerId": viewerId}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":       primitive.NewObjectID(),
+			"createdAt": time.Now(),
+			"expiresAt": story.ExpiresAt,
+			"ownerId":   story.UserID,
+		},
+	}
+	storyViewsCollection := services.GetMongoDBCollection(config.StoryViewsCollection)
+	result, err := storyViewsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil || result.UpsertedCount == 0 {
+		return err
+	}
+
+	storiesCollection := services.GetMongoDBCollection(config.StoriesCollection)
+	_, err = storiesCollection.UpdateByID(ctx, story.ID, bson.M{"$inc": bson.M{"viewersCount": 1}})
+	return err
+}
+
+// FindArchivedStories returns the stories of the user's archive in the order of storyIds
+func FindArchivedStories(ctx context.Context, userId primitive.ObjectID, storyIds []primitive.ObjectID) ([]Story, error) {
+	collection := services.GetMongoDBCollection(config.StoryArchiveCollection)
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": storyIds}, "userId": userId})
+	if err != nil {
+		return nil, err
+	}
+
+	archived := []Story{}
+	err = cursor.All(ctx, &archived)
+	if err != nil {
+		return nil, err
+	}
+
+	stories := []Story{}
+	for _, storyId := range storyIds {
+		for _, story := range archived {
+			if story.ID == storyId {
+				stories = append(stories, story)
+			}
+		}
+	}
+
+	return stories, nil
+}
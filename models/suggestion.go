@@ -0,0 +1,184 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/pagination"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	FollowsYouSuggestionReason = "followsYou"
+	MutualSuggestionReason     = "mutual"
+	PopularSuggestionReason    = "popular"
+)
+
+// A DismissedSuggestion keeps DismissedUserID out of the suggestions of UserID
+type DismissedSuggestion struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	DismissedUserID primitive.ObjectID `bson:"dismissedUserId" json:"dismissedUserId"`
+	UserID          primitive.ObjectID `bson:"userId" json:"userId"`
+}
+
+// DismissSuggestion does nothing if the suggestion was already dismissed
+func DismissSuggestion(ctx context.Context, userId, dismissedUserId primitive.ObjectID) error {
+	dismissal := DismissedSuggestion{
+		ID:              primitive.NewObjectID(),
+		CreatedAt:       time.Now(),
+		DismissedUserID: dismissedUserId,
+		UserID:          userId,
+	}
+	filter := bson.M{"userId": userId, "dismissedUserId": dismissedUserId}
+	collection := services.GetMongoDBCollection(config.DismissedSuggestionsCollection)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": dismissal}, options.Update().SetUpsert(true))
+	return err
+}
+
+// findSuggestionExcludedIds returns userId and the users it has requested to follow, has a block with or has dismissed
+// from its suggestions. The users it follows are left out by SuggestionsPipeline
+func findSuggestionExcludedIds(ctx context.Context, userId primitive.ObjectID) (bson.A, error) {
+	excludedIds := bson.A{userId}
+	hiddenIds, err := FindHiddenUserIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	excludedIds = append(excludedIds, hiddenIds...)
+	sources := []struct {
+		collection string
+		filter     bson.M
+		field      string
+	}{
+		{config.FollowRequestsCollection, bson.M{"requesterId": userId}, "userId"},
+		{config.DismissedSuggestionsCollection, bson.M{"userId": userId}, "dismissedUserId"},
+	}
+	for _, source := range sources {
+		collection := services.GetMongoDBCollection(source.collection)
+		ids, err := collection.Distinct(ctx, source.field, source.filter)
+		if err != nil {
+			return nil, err
+		}
+
+		excludedIds = append(excludedIds, ids...)
+	}
+
+	return excludedIds, nil
+}
+
+// SuggestionsPipeline ranks the accounts userId may want to follow, to be run on the follows collection.
+// Accounts followed by more of the users userId follows come first, then accounts that follow userId,
+// and popular accounts fill the rest of the at most config.MaxSuggestionsLength suggestions
+func SuggestionsPipeline(ctx context.Context, userId primitive.ObjectID, params *pagination.Params) (bson.A, error) {
+	excludedIds, err := findSuggestionExcludedIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit + 1
+	if params.Skip+limit > config.MaxSuggestionsLength {
+		limit = config.MaxSuggestionsLength - params.Skip
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	// Candidates are the accounts followed by the users userId follows, and the accounts that follow userId
+	return bson.A{
+		bson.M{"$match": bson.M{"followerId": userId}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.FollowsCollection,
+				"let":  bson.M{"userId": "$followeeId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$followerId", "$$userId"}}}},
+					bson.M{"$project": bson.M{"followeeId": 1}},
+				},
+				"as": "mutualFollows",
+			},
+		},
+		bson.M{"$unwind": "$mutualFollows"},
+		bson.M{"$project": bson.M{"candidateId": "$mutualFollows.followeeId", "followsYou": bson.M{"$literal": false}}},
+		bson.M{
+			"$unionWith": bson.M{
+				"coll": config.FollowsCollection,
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"followeeId": userId}},
+					bson.M{"$project": bson.M{"candidateId": "$followerId", "followsYou": bson.M{"$literal": true}}},
+				},
+			},
+		},
+		bson.M{"$match": bson.M{"candidateId": bson.M{"$nin": excludedIds}}},
+		bson.M{
+			"$group": bson.M{
+				"_id":         "$candidateId",
+				"followsYou":  bson.M{"$max": "$followsYou"},
+				"mutualCount": bson.M{"$sum": bson.M{"$cond": bson.A{"$followsYou", 0, 1}}},
+			},
+		},
+		FollowingLookupStage(userId, "_id", "follow"),
+		bson.M{"$match": bson.M{"follow": bson.M{"$size": 0}}},
+		bson.M{
+			"$unionWith": bson.M{
+				"coll": config.UsersCollection,
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"_id": bson.M{"$nin": excludedIds}}},
+					bson.M{"$sort": bson.D{{Key: "followersCount", Value: -1}, {Key: "_id", Value: -1}}},
+					FollowingLookupStage(userId, "_id", "follow"),
+					bson.M{"$match": bson.M{"follow": bson.M{"$size": 0}}},
+					bson.M{"$limit": config.MaxSuggestionsLength},
+					bson.M{"$project": bson.M{"followsYou": bson.M{"$literal": false}, "mutualCount": bson.M{"$literal": 0}}},
+				},
+			},
+		},
+		bson.M{
+			"$group": bson.M{
+				"_id":         "$_id",
+				"followsYou":  bson.M{"$max": "$followsYou"},
+				"mutualCount": bson.M{"$max": "$mutualCount"},
+			},
+		},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$_id"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"username": 1, "image": 1, "name": 1, "followersCount": 1}},
+				},
+				"as": "user",
+			},
+		},
+		bson.M{"$unwind": "$user"},
+		bson.M{"$sort": bson.D{{Key: "mutualCount", Value: -1}, {Key: "followsYou", Value: -1}, {Key: "user.followersCount", Value: -1}, {Key: "_id", Value: -1}}},
+		bson.M{"$skip": params.Skip},
+		bson.M{"$limit": limit},
+		bson.M{
+			"$replaceRoot": bson.M{
+				"newRoot": bson.M{
+					"$mergeObjects": bson.A{
+						"$user",
+						bson.M{
+							"mutualCount": "$mutualCount",
+							"reason": bson.M{
+								"$switch": bson.M{
+									"branches": bson.A{
+										bson.M{"case": bson.M{"$gt": bson.A{"$mutualCount", 0}}, "then": MutualSuggestionReason},
+										bson.M{"case": "$followsYou", "then": FollowsYouSuggestionReason},
+									},
+									"default": PopularSuggestionReason,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
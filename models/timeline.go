@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const timelineWriteBatchSize = 1000
+
+// TimelineEntry places a post in the home feed of the user identified by UserID
+type TimelineEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Audience  string             `bson:"audience,omitempty" json:"audience,omitempty"`
+	AuthorID  interface{}        `bson:"authorId" json:"authorId"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	PostID    primitive.ObjectID `bson:"postId" json:"postId"`
+	UserID    interface{}        `bson:"userId" json:"userId"`
+}
+
+// Posts of users with more followers than the limit are merged into home feeds at read time. Users stay fanned out
+// on read after dropping back under the limit, since their posts meanwhile never reached their followers'
+// timelines, until the timeline backfill job fills those timelines
+func IsFanOutOnRead(user *User) bool {
+	return user.FanOutOnRead || user.FollowersCount > config.TimelineFanOutLimit
+}
+
+func FanOutOnReadFilter() bson.M {
+	return bson.M{"$or": bson.A{bson.M{"fanOutOnRead": true}, bson.M{"followersCount": bson.M{"$gt": config.TimelineFanOutLimit}}}}
+}
+
+func AddPostsToTimelines(ctx context.Context, userIds bson.A, posts ...Post) error {
+	upsert := true
+	operations := []mongo.WriteModel{}
+	collection := services.GetMongoDBCollection(config.TimelinesCollection)
+	bulkWriteOptions := options.BulkWrite().SetOrdered(false)
+
+	for _, userId := range userIds {
+		for _, post := range posts {
+			entry := TimelineEntry{
+				ID:        primitive.NewObjectID(),
+				Audience:  post.Audience,
+				AuthorID:  post.UserID,
+				CreatedAt: post.CreatedAt,
+				PostID:    post.ID,
+				UserID:    userId,
+			}
+			operations = append(operations, &mongo.UpdateOneModel{
+				Filter: bson.M{"userId": userId, "postId": post.ID},
+				Update: bson.M{"$setOnInsert": entry},
+				Upsert: &upsert,
+			})
+
+			if len(operations) < timelineWriteBatchSize {
+				continue
+			}
+
+			if _, err := collection.BulkWrite(ctx, operations, bulkWriteOptions); err != nil {
+				return err
+			}
+			operations = []mongo.WriteModel{}
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	_, err := collection.BulkWrite(ctx, operations, bulkWriteOptions)
+	return err
+}
+
+// FindFanOutOnReadFollowingIds returns the ids of the users followed by followerId whose posts are fanned out on read
+func FindFanOutOnReadFollowingIds(ctx context.Context, followerId interface{}) (bson.A, error) {
+	userMatch := FanOutOnReadFilter()
+	userMatch["$expr"] = bson.M{"$eq": bson.A{"$_id", "$$userId"}}
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"followerId": followerId}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$followeeId"},
+				"pipeline": bson.A{
+					bson.M{"$match": userMatch},
+					bson.M{"$project": bson.M{"_id": 1}},
+				},
+				"as": "users",
+			},
+		},
+		bson.M{"$match": bson.M{"users.0": bson.M{"$exists": true}}},
+		bson.M{"$project": bson.M{"followeeId": 1}},
+	}
+	collection := services.GetMongoDBCollection(config.FollowsCollection)
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	follows := []Follow{}
+	err = cursor.All(ctx, &follows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := bson.A{}
+	for _, follow := range follows {
+		ids = append(ids, follow.FolloweeID)
+	}
+
+	return ids, nil
+}
+
+// BackfillTimeline adds the latest posts of the author to the user's timeline, e.g after a follow
+func BackfillTimeline(ctx context.Context, userId, authorId interface{}) error {
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1, "audience": 1, "createdAt": 1, "userId": 1})
+	findOptions = findOptions.SetSort(bson.M{"createdAt": -1}).SetLimit(config.CommonPaginationLength)
+
+	postsCollection := services.GetMongoDBCollection(config.PostsCollection)
+	cursor, err := postsCollection.Find(ctx, ExcludeHiddenPosts(bson.M{"userId": authorId}), findOptions)
+	if err != nil {
+		return err
+	}
+
+	posts := []Post{}
+	err = cursor.All(ctx, &posts)
+	if err != nil {
+		return err
+	}
+
+	return AddPostsToTimelines(ctx, bson.A{userId}, posts...)
+}
@@ -30,22 +30,28 @@ type PasswordConfig struct {
 }
 
 type User struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty"  json:"_id,omitempty"`
-	AccountVerified bool               `bson:"accountVerified" json:"accountVerified"`
-	Bio             string             `bson:"bio" json:"bio"`
-	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt,omitempty"`
-	Email           string             `bson:"email" json:"email,omitempty" binding:"email,max=255"`
-	FollowersCount  int                `bson:"followersCount" json:"followersCount"`
-	FollowingCount  int                `bson:"followingCount" json:"followingCount"`
-	Gender          string             `bson:"gender" json:"gender,omitempty"`
-	Image           string             `bson:"image" json:"image"`
-	Name            string             `bson:"name" json:"name" binding:"required,name,max=50"`
-	Password        string             `bson:"password" json:"password,omitempty"  binding:"required,min=6"`
-	PostsCount      int                `bson:"postsCount" json:"postsCount"`
-	Posts           []bson.M           `bson:"posts" json:"posts"`
-	PhoneNo         string             `bson:"phoneNo" json:"phoneNo,omitempty"`
-	Username        string             `bson:"username" json:"username" binding:"username"`
-	Website         string             `bson:"website" json:"website"`
+	ID                  primitive.ObjectID     `bson:"_id,omitempty"  json:"_id,omitempty"`
+	AccountVerified     bool                   `bson:"accountVerified" json:"accountVerified"`
+	Bio                 string                 `bson:"bio" json:"bio"`
+	CommentFilters      *CommentFilterSettings `bson:"commentFilters,omitempty" json:"-"`
+	CreatedAt           time.Time              `bson:"createdAt" json:"createdAt,omitempty"`
+	DeactivatedAt       *time.Time             `bson:"deactivatedAt,omitempty" json:"-"`
+	DeletionRequestedAt *time.Time             `bson:"deletionRequestedAt,omitempty" json:"-"`
+	Email               string                 `bson:"email" json:"email,omitempty" binding:"email,max=255"`
+	FanOutOnRead        bool                   `bson:"fanOutOnRead,omitempty" json:"-"`
+	FollowersCount      int                    `bson:"followersCount" json:"followersCount"`
+	FollowingCount      int                    `bson:"followingCount" json:"followingCount"`
+	Gender              string                 `bson:"gender" json:"gender,omitempty"`
+	Image               string                 `bson:"image" json:"image"`
+	IsPrivate           bool                   `bson:"isPrivate" json:"isPrivate"`
+	MutedNotifications  []string               `bson:"mutedNotifications,omitempty" json:"-"`
+	Name                string                 `bson:"name" json:"name" binding:"required,name,max=50"`
+	Password            string                 `bson:"password" json:"password,omitempty"  binding:"required,min=6"`
+	PostsCount          int                    `bson:"postsCount" json:"postsCount"`
+	Posts               []bson.M               `bson:"posts" json:"posts"`
+	PhoneNo             string                 `bson:"phoneNo" json:"phoneNo,omitempty"`
+	Username            string                 `bson:"username" json:"username" binding:"username"`
+	Website             string                 `bson:"website" json:"website"`
 }
 
 func (user *User) ComparePassword(password string) (bool, error) {
@@ -136,6 +142,7 @@ func (user *User) NormalizeFields(new bool) {
 }
 
 type FindUserResult struct {
+	Error        error
 	User         *User
 	ResponseBody interface{}
 	StatusCode   int
@@ -149,6 +156,7 @@ func FindUser(ctx context.Context, filter interface{}, options ...*options.FindO
 		return &FindUserResult{
 			ResponseBody: gin.H{"message": "User not found"},
 			StatusCode:   http.StatusNotFound,
+			Error:        err,
 		}
 	}
 
@@ -156,6 +164,7 @@ func FindUser(ctx context.Context, filter interface{}, options ...*options.FindO
 		return &FindUserResult{
 			ResponseBody: gin.H{"message": err.Error()},
 			StatusCode:   http.StatusInternalServerError,
+			Error:        err,
 		}
 	}
 
@@ -11,10 +11,6 @@ import (
 type UserDetails struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty"  json:"_id"`
 	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
-	Followers       bson.A             `bson:"followers" json:"followers"`
-	FollowersCount  int                `bson:"followersCount" json:"followersCount"`
-	Following       bson.A             `bson:"following" json:"following"`
-	FollowingCount  int                `bson:"followingCount" json:"followingCount"`
 	SavedPosts      bson.A             `bson:"savedPosts" json:"savedPosts"`
 	SavedPostsCount int                `bson:"savedPostsCount" json:"savedPostsCount"`
 	UserID          interface{}        `bson:"userId,omitempty" json:"userId"`
@@ -31,21 +27,6 @@ func NewUserDetails(exclude []interface{}) bson.M {
 		userDetails["createdAt"] = time.Now()
 	}
 
-	if !helpers.Contains(exclude, "followers") {
-		userDetails["followers"] = bson.A{}
-	}
-
-	if !helpers.Contains(exclude, "followersCount") {
-		userDetails["followersCount"] = 0
-	}
-
-	if !helpers.Contains(exclude, "following") {
-		userDetails["following"] = bson.A{}
-	}
-
-	if !helpers.Contains(exclude, "followingCount") {
-		userDetails["followingCount"] = 0
-	}
 	if !helpers.Contains(exclude, "savedPosts") {
 		userDetails["savedPosts"] = bson.A{}
 	}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CanViewUserContent reports whether the viewer may see the posts, friendships and comments of owner.
+// Anonymous viewers are represented by primitive.NilObjectID.
+func CanViewUserContent(ctx context.Context, viewerId primitive.ObjectID, owner *User) (bool, error) {
+	if !owner.IsPrivate || viewerId == owner.ID {
+		return true, nil
+	}
+
+	if viewerId.IsZero() {
+		return false, nil
+	}
+
+	return IsFollowing(ctx, viewerId, owner.ID)
+}
+
+func CanViewUserContentByID(ctx context.Context, viewerId primitive.ObjectID, ownerId interface{}) (bool, error) {
+	findOneOptions := options.FindOne().SetProjection(bson.M{"isPrivate": 1})
+	result := FindUser(ctx, bson.M{"_id": ownerId}, findOneOptions)
+	if result.User == nil && result.StatusCode == http.StatusNotFound {
+		return true, nil
+	}
+
+	if result.User == nil {
+		return false, result.Error
+	}
+
+	return CanViewUserContent(ctx, viewerId, result.User)
+}
+
+// VisibleAuthorStages filters out documents whose author, referenced by userId, is deactivated, has a block with the viewer
+// or is private and not followed by the viewer
+func VisibleAuthorStages(ctx context.Context, viewerId primitive.ObjectID) (bson.A, error) {
+	hiddenIds, err := FindHiddenUserIds(ctx, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := bson.A{
+		bson.M{"$match": bson.M{"userId": bson.M{"$nin": hiddenIds}}},
+		bson.M{
+			"$lookup": bson.M{
+				"from": config.UsersCollection,
+				"let":  bson.M{"userId": "$userId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$userId"}}}},
+					bson.M{"$project": bson.M{"isPrivate": 1}},
+				},
+				"as": "author",
+			},
+		},
+	}
+	if viewerId.IsZero() {
+		return append(stages,
+			bson.M{"$match": bson.M{"author.isPrivate": bson.M{"$ne": true}}},
+			bson.M{"$project": bson.M{"author": 0}},
+		), nil
+	}
+
+	return append(stages,
+		FollowingLookupStage(viewerId, "userId", "viewerFollow"),
+		bson.M{
+			"$match": bson.M{
+				"$or": bson.A{
+					bson.M{"author.isPrivate": bson.M{"$ne": true}},
+					bson.M{"userId": viewerId},
+					bson.M{"viewerFollow.0": bson.M{"$exists": true}},
+				},
+			},
+		},
+		bson.M{"$project": bson.M{"author": 0, "viewerFollow": 0}},
+	), nil
+}
+
+// PostAudienceStages keep the posts, whose author id is stored under authorField, that the viewer is in the audience
+// of. Posts without an audience are public and posts of deactivated authors are left out
+func PostAudienceStages(ctx context.Context, viewerId primitive.ObjectID, authorField string) (bson.A, error) {
+	deactivatedIds, err := FindDeactivatedUserIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictedAudiences := bson.A{FollowersPostAudience, CloseFriendsPostAudience}
+	if viewerId.IsZero() {
+		return bson.A{bson.M{"$match": bson.M{"audience": bson.M{"$nin": restrictedAudiences}, authorField: bson.M{"$nin": deactivatedIds}}}}, nil
+	}
+
+	return bson.A{
+		bson.M{"$match": bson.M{authorField: bson.M{"$nin": deactivatedIds}}},
+		FollowingLookupStage(viewerId, authorField, "viewerFollow"),
+		CloseFriendLookupStage(authorField, viewerId, "viewerCloseFriend"),
+		bson.M{
+			"$match": bson.M{
+				"$or": bson.A{
+					bson.M{"audience": bson.M{"$nin": restrictedAudiences}},
+					bson.M{authorField: viewerId},
+					bson.M{"audience": FollowersPostAudience, "viewerFollow.0": bson.M{"$exists": true}},
+					bson.M{"audience": CloseFriendsPostAudience, "viewerCloseFriend.0": bson.M{"$exists": true}},
+				},
+			},
+		},
+		bson.M{"$project": bson.M{"viewerCloseFriend": 0, "viewerFollow": 0}},
+	}, nil
+}
+
+// AuthorPostAudienceFilter matches the posts of authorId that the viewer is in the audience of
+func AuthorPostAudienceFilter(ctx context.Context, viewerId primitive.ObjectID, authorId interface{}) (bson.M, error) {
+	excludedAudiences := bson.A{}
+	for _, audience := range []string{FollowersPostAudience, CloseFriendsPostAudience} {
+		inAudience, err := IsInPostAudience(ctx, viewerId, authorId, audience)
+		if err != nil {
+			return nil, err
+		}
+
+		if !inAudience {
+			excludedAudiences = append(excludedAudiences, audience)
+		}
+	}
+
+	return bson.M{"userId": authorId, "audience": bson.M{"$nin": excludedAudiences}}, nil
+}
+
+// IsInPostAudience reports whether the viewer may see a post of authorId shared with audience
+func IsInPostAudience(ctx context.Context, viewerId primitive.ObjectID, authorId interface{}, audience string) (bool, error) {
+	if viewerId == authorId {
+		return true, nil
+	}
+
+	switch audience {
+	case FollowersPostAudience:
+		if viewerId.IsZero() {
+			return false, nil
+		}
+
+		return IsFollowing(ctx, viewerId, authorId)
+	case CloseFriendsPostAudience:
+		if viewerId.IsZero() {
+			return false, nil
+		}
+
+		return IsCloseFriend(ctx, authorId, viewerId)
+	default:
+		return true, nil
+	}
+}
+
+// FilterPostsByAudience drops the posts of authorId, such as those embedded in the user document, that the viewer
+// is not in the audience of
+func FilterPostsByAudience(ctx context.Context, viewerId primitive.ObjectID, authorId interface{}, posts []bson.M) ([]bson.M, error) {
+	allowed := map[string]bool{}
+	filtered := []bson.M{}
+	for _, post := range posts {
+		audience, _ := post["audience"].(string)
+		if _, ok := allowed[audience]; !ok {
+			inAudience, err := IsInPostAudience(ctx, viewerId, authorId, audience)
+			if err != nil {
+				return nil, err
+			}
+
+			allowed[audience] = inAudience
+		}
+
+		if allowed[audience] {
+			filtered = append(filtered, post)
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("cursor is not valid")
+
+// Cursor points at the last document of a page. ItemID is only set for bucketed lists,
+// where CreatedAt and ID identify the bucket rather than the item.
+type Cursor struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	ID        primitive.ObjectID `json:"_id"`
+	ItemID    primitive.ObjectID `json:"itemId"`
+}
+
+func (cursor *Cursor) Encode() (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + sign(encodedPayload), nil
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(sign(parts[0]))) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(config.CursorSecret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEncodeAndDecodeCursor(t *testing.T) {
+	cursor := &Cursor{CreatedAt: time.Now().UTC().Truncate(time.Millisecond), ID: primitive.NewObjectID()}
+	value, err := cursor.Encode()
+	assert.NoError(t, err)
+
+	decodedCursor, err := DecodeCursor(value)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decodedCursor)
+}
+
+func TestDecodeCursorFailsIfTampered(t *testing.T) {
+	cursor := &Cursor{CreatedAt: time.Now(), ID: primitive.NewObjectID()}
+	value, err := cursor.Encode()
+	assert.NoError(t, err)
+
+	otherValue, err := (&Cursor{CreatedAt: time.Now(), ID: primitive.NewObjectID()}).Encode()
+	assert.NoError(t, err)
+
+	_, err = DecodeCursor(otherValue[:len(otherValue)/2] + value[len(value)/2:])
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodeCursor("invalid")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewPage(t *testing.T) {
+	params := &Params{Limit: 2}
+	items := []bson.M{
+		{"_id": primitive.NewObjectID(), "createdAt": primitive.NewDateTimeFromTime(time.Now())},
+		{"_id": primitive.NewObjectID(), "createdAt": primitive.NewDateTimeFromTime(time.Now())},
+		{"_id": primitive.NewObjectID(), "createdAt": primitive.NewDateTimeFromTime(time.Now())},
+	}
+
+	page, err := NewPage(items, params, "createdAt", "_id")
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.True(t, page.HasNextPage)
+
+	cursor, err := DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, items[1]["_id"], cursor.ID)
+
+	page, err = NewPage(items[:1], params, "createdAt", "_id")
+	assert.NoError(t, err)
+	assert.False(t, page.HasNextPage)
+	assert.Empty(t, page.NextCursor)
+}
@@ -0,0 +1,236 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bucketed lists attach the position of each item under this field so NewPage can build the next cursor
+const CursorField = "_cursor"
+
+type Params struct {
+	Cursor *Cursor
+	Limit  int64
+	Skip   int64
+	// ExcludedIds are left out of bucketed lists, e.g users that have a block with the viewer
+	ExcludedIds bson.A
+	// ItemFilter is matched against the documents bucketed lists look up, e.g posts the viewer is not in the audience of
+	ItemFilter bson.M
+	// ItemStages run after ItemFilter on the looked up documents, e.g to leave out posts of private authors
+	ItemStages bson.A
+}
+
+type Page struct {
+	Items       []bson.M `json:"items"`
+	NextCursor  string   `json:"nextCursor"`
+	HasNextPage bool     `json:"hasNextPage"`
+}
+
+func ParseQuery(c *gin.Context) (*Params, error) {
+	params := &Params{Limit: config.CommonPaginationLength}
+
+	limitQueryValue := c.Query("limit")
+	if limitQueryValue != "" {
+		limit, err := strconv.ParseInt(limitQueryValue, 10, 32)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("%v is not a positive integer", limitQueryValue)
+		}
+
+		params.Limit = limit
+	}
+
+	if params.Limit > config.MaxPaginationLength {
+		params.Limit = config.MaxPaginationLength
+	}
+
+	skipQueryValue := c.Query("skip")
+	if skipQueryValue != "" {
+		skip, err := strconv.ParseInt(skipQueryValue, 10, 32)
+		if err != nil || skip < 0 {
+			return nil, fmt.Errorf("%v is not a valid integer", skipQueryValue)
+		}
+
+		params.Skip = skip
+	}
+
+	cursorQueryValue := c.Query("cursor")
+	if cursorQueryValue != "" {
+		if params.Skip > 0 {
+			return nil, fmt.Errorf("cursor and skip cannot be used together")
+		}
+
+		cursor, err := DecodeCursor(cursorQueryValue)
+		if err != nil {
+			return nil, err
+		}
+
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// Stages sorts documents from newest to oldest and returns the requested page plus one
+// extra document, which NewPage uses to tell whether there is a next page.
+func (params *Params) Stages(createdAtField, idField string) bson.A {
+	stages := bson.A{}
+	if params.Cursor != nil {
+		stages = append(stages, bson.M{
+			"$match": bson.M{
+				"$or": bson.A{
+					bson.M{createdAtField: bson.M{"$lt": params.Cursor.CreatedAt}},
+					bson.M{createdAtField: params.Cursor.CreatedAt, idField: bson.M{"$lt": params.Cursor.ID}},
+				},
+			},
+		})
+	}
+
+	stages = append(stages, bson.M{"$sort": bson.D{{Key: createdAtField, Value: -1}, {Key: idField, Value: -1}}})
+	if params.Skip > 0 {
+		stages = append(stages, bson.M{"$skip": params.Skip})
+	}
+
+	return append(stages, bson.M{"$limit": params.Limit + 1})
+}
+
+// BucketStages pages through the ids stored in the array field of bucket documents such as
+// user_details, replacing each id with the matching document of the "from" collection.
+func (params *Params) BucketStages(field, from string, projection interface{}) bson.A {
+	stages := bson.A{}
+	cursorPosition := bson.M{"$literal": -1}
+	if params.Cursor != nil {
+		stages = append(stages, bson.M{
+			"$match": bson.M{
+				"$or": bson.A{
+					bson.M{"createdAt": bson.M{"$lt": params.Cursor.CreatedAt}},
+					bson.M{"createdAt": params.Cursor.CreatedAt, "_id": bson.M{"$lte": params.Cursor.ID}},
+				},
+			},
+		})
+		cursorPosition = bson.M{
+			"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$_id", params.Cursor.ID}},
+				bson.M{"$indexOfArray": bson.A{"$" + field, params.Cursor.ItemID}},
+				-1,
+			},
+		}
+	}
+
+	itemMatch := bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$itemId"}}}
+	for key, value := range params.ItemFilter {
+		itemMatch[key] = value
+	}
+
+	itemPipeline := append(bson.A{bson.M{"$match": itemMatch}}, params.ItemStages...)
+	itemPipeline = append(itemPipeline, bson.M{"$project": projection})
+
+	stages = append(stages,
+		bson.M{"$sort": bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		bson.M{"$project": bson.M{"createdAt": 1, field: 1, "cursorPosition": cursorPosition}},
+		bson.M{"$unwind": bson.M{"path": "$" + field, "includeArrayIndex": "position"}},
+		bson.M{
+			"$match": bson.M{
+				"$expr": bson.M{"$gt": bson.A{"$position", "$cursorPosition"}},
+				field:   bson.M{"$nin": params.excludedIds()},
+			},
+		},
+		bson.M{
+			"$lookup": bson.M{
+				"from":     from,
+				"let":      bson.M{"itemId": "$" + field},
+				"pipeline": itemPipeline,
+				"as":       "items",
+			},
+		},
+		bson.M{"$unwind": "$items"},
+	)
+	if params.Skip > 0 {
+		stages = append(stages, bson.M{"$skip": params.Skip})
+	}
+
+	return append(stages,
+		bson.M{"$limit": params.Limit + 1},
+		bson.M{
+			"$replaceRoot": bson.M{
+				"newRoot": bson.M{
+					"$mergeObjects": bson.A{
+						"$items",
+						bson.M{CursorField: bson.M{"createdAt": "$createdAt", "_id": "$_id", "itemId": "$" + field}},
+					},
+				},
+			},
+		},
+	)
+}
+
+func (params *Params) excludedIds() bson.A {
+	if params.ExcludedIds == nil {
+		return bson.A{}
+	}
+
+	return params.ExcludedIds
+}
+
+// NewPage trims the extra document fetched by Stages or BucketStages and encodes the
+// position of the last document as the next cursor.
+func NewPage(items []bson.M, params *Params, createdAtField, idField string) (*Page, error) {
+	page := &Page{Items: items}
+	if int64(len(items)) > params.Limit {
+		page.Items = items[:params.Limit]
+		page.HasNextPage = true
+	}
+
+	var cursor *Cursor
+	for index, item := range page.Items {
+		position, hasPosition := item[CursorField].(bson.M)
+		delete(item, CursorField)
+		if !page.HasNextPage || index != len(page.Items)-1 {
+			continue
+		}
+
+		if hasPosition {
+			cursor = newCursor(position["createdAt"], position["_id"], position["itemId"])
+		} else {
+			cursor = newCursor(item[createdAtField], item[idField], nil)
+		}
+
+		if cursor == nil {
+			return nil, fmt.Errorf("could not build cursor from %v and %v", createdAtField, idField)
+		}
+	}
+
+	if cursor != nil {
+		nextCursor, err := cursor.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		page.NextCursor = nextCursor
+	}
+
+	return page, nil
+}
+
+func newCursor(createdAt, id, itemId interface{}) *Cursor {
+	dateTime, ok := createdAt.(primitive.DateTime)
+	if !ok {
+		return nil
+	}
+
+	objectId, ok := id.(primitive.ObjectID)
+	if !ok {
+		return nil
+	}
+
+	cursor := &Cursor{CreatedAt: dateTime.Time(), ID: objectId}
+	if itemObjectId, ok := itemId.(primitive.ObjectID); ok {
+		cursor.ItemID = itemObjectId
+	}
+
+	return cursor
+}
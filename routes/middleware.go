@@ -1,13 +1,18 @@
 package routes
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/gin-gonic/gin"
 )
 
+// Authorizer also rejects the tokens of deactivated accounts, including those pending deletion, since logging out
+// only clears the cookie of the current device
 func Authorizer(credentialsRequired bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		accessToken, err := c.Cookie(config.AccessTokenCookieName)
@@ -37,6 +42,25 @@ func Authorizer(credentialsRequired bool) gin.HandlerFunc {
 			return
 		}
 
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second*3)
+		defer cancel()
+
+		deactivated, err := models.IsDeactivated(ctx, user.(*services.AccessTokenClaim).ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		if deactivated && credentialsRequired {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "This account is deactivated"})
+			return
+		}
+
+		if deactivated {
+			c.Next()
+			return
+		}
+
 		c.Set("user", user)
 		c.Next()
 	}
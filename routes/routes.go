@@ -56,42 +56,126 @@ func SetupRouter() *gin.Engine {
 
 	commentRouter := router.Group("comments")
 	{
-		commentRouter.GET("", handlers.GetComments)
+		commentRouter.GET("", Authorizer(false), handlers.GetComments)
 		commentRouter.POST("", Authorizer(true), handlers.CreateComment)
+		commentRouter.GET("/hidden", Authorizer(true), handlers.GetHiddenComments)
+		commentRouter.GET("/:_id/thread", Authorizer(false), handlers.GetCommentThread)
+		commentRouter.PATCH("/:_id", Authorizer(true), handlers.UpdateComment)
+		commentRouter.POST("/:_id/hide", Authorizer(true), handlers.HideComment)
+		commentRouter.POST("/:_id/unhide", Authorizer(true), handlers.UnhideComment)
+		commentRouter.POST("/:_id/pin", Authorizer(true), handlers.PinComment)
+		commentRouter.POST("/:_id/unpin", Authorizer(true), handlers.UnpinComment)
+		commentRouter.POST("/:_id/restore", Authorizer(true), handlers.RestoreComment)
 		commentRouter.DELETE("/:_id", Authorizer(true), handlers.DeleteComment)
 	}
 
+	conversationRouter := router.Group("conversations", Authorizer(true))
+	{
+		conversationRouter.GET("", handlers.GetConversations)
+		conversationRouter.POST("", handlers.CreateConversation)
+		conversationRouter.POST("/:_id/accept", handlers.AcceptConversation)
+		conversationRouter.POST("/:_id/decline", handlers.DeclineConversation)
+		conversationRouter.POST("/:_id/read", handlers.ReadConversation)
+		conversationRouter.GET("/:_id/messages", handlers.GetMessages)
+		conversationRouter.POST("/:_id/messages", handlers.CreateMessage)
+		conversationRouter.DELETE("/:_id/messages/:messageId", handlers.DeleteMessage)
+	}
+
+	router.GET("/events", Authorizer(true), handlers.StreamEvents)
+
 	friendshipRouter := router.Group("friendships")
 	{
-		friendshipRouter.GET("/:_id/followers", handlers.GetUserFollowers)
-		friendshipRouter.GET("/:_id/following", handlers.GetUserFollowing)
+		friendshipRouter.GET("/requests", Authorizer(true), handlers.GetFollowRequests)
+		friendshipRouter.GET("/close-friends", Authorizer(true), handlers.GetCloseFriends)
+		friendshipRouter.GET("/:_id/followers", Authorizer(false), handlers.GetUserFollowers)
+		friendshipRouter.GET("/:_id/following", Authorizer(false), handlers.GetUserFollowing)
+		friendshipRouter.GET("/:_id/mutual", Authorizer(true), handlers.GetMutualFollowers)
+		friendshipRouter.GET("/:_id/relationship", Authorizer(true), handlers.GetRelationship)
 		friendshipRouter.POST("/:_id/follow", Authorizer(true), handlers.FollowUser)
 		friendshipRouter.POST("/:_id/unfollow", Authorizer(true), handlers.UnfollowUser)
+		friendshipRouter.POST("/:_id/remove-follower", Authorizer(true), handlers.RemoveFollower)
+		friendshipRouter.POST("/:_id/add-close-friend", Authorizer(true), handlers.AddCloseFriend)
+		friendshipRouter.POST("/:_id/remove-close-friend", Authorizer(true), handlers.RemoveCloseFriend)
+		friendshipRouter.POST("/:_id/approve", Authorizer(true), handlers.ApproveFollowRequest)
+		friendshipRouter.POST("/:_id/reject", Authorizer(true), handlers.RejectFollowRequest)
+	}
+
+	notificationRouter := router.Group("notifications", Authorizer(true))
+	{
+		notificationRouter.GET("", handlers.GetNotifications)
+		notificationRouter.POST("/read", handlers.ReadNotifications)
+		notificationRouter.POST("/:_id/read", handlers.ReadNotification)
+		notificationRouter.GET("/settings", handlers.GetNotificationSettings)
+		notificationRouter.PATCH("/settings", handlers.UpdateNotificationSettings)
 	}
 
 	postRouter := router.Group("posts")
 	{
 		postRouter.POST("", Authorizer(true), handlers.CreatePost)
 		postRouter.POST("/:_id/save", Authorizer(true), handlers.SavePost)
+		postRouter.POST("/:_id/unsave", Authorizer(true), handlers.UnsavePost)
+		postRouter.POST("/:_id/archive", Authorizer(true), handlers.ArchivePost)
+		postRouter.POST("/:_id/unarchive", Authorizer(true), handlers.UnarchivePost)
+		postRouter.POST("/:_id/restore", Authorizer(true), handlers.RestorePost)
+		postRouter.PATCH("/:_id/comment-settings", Authorizer(true), handlers.UpdatePostCommentSettings)
 		postRouter.DELETE("/:_id", Authorizer(true), handlers.DeletePost)
-		postRouter.GET("/:_id", handlers.GetPost)
+		postRouter.GET("/:_id", Authorizer(false), handlers.GetPost)
 	}
 
 	replyRouter := router.Group("replies")
 	{
-		replyRouter.GET("", handlers.GetReplies)
+		replyRouter.GET("", Authorizer(false), handlers.GetReplies)
 		replyRouter.POST("", Authorizer(true), handlers.CreateReply)
+		replyRouter.GET("/hidden", Authorizer(true), handlers.GetHiddenReplies)
+		replyRouter.PATCH("/:_id", Authorizer(true), handlers.UpdateReply)
+		replyRouter.POST("/:_id/hide", Authorizer(true), handlers.HideReply)
+		replyRouter.POST("/:_id/unhide", Authorizer(true), handlers.UnhideReply)
+		replyRouter.POST("/:_id/restore", Authorizer(true), handlers.RestoreReply)
 		replyRouter.DELETE("/:_id", Authorizer(true), handlers.DeleteReply)
 	}
 
+	storyRouter := router.Group("stories", Authorizer(true))
+	{
+		storyRouter.POST("", handlers.CreateStory)
+		storyRouter.GET("/feed", handlers.GetStoriesFeed)
+		storyRouter.GET("/archive", handlers.GetStoryArchive)
+		storyRouter.POST("/:_id/view", handlers.ViewStory)
+		storyRouter.GET("/:_id/viewers", handlers.GetStoryViewers)
+		storyRouter.DELETE("/:_id", handlers.DeleteStory)
+	}
+
 	userRouter := router.Group("users")
 	{
-		userRouter.GET("/:username", handlers.GetUser)
-		userRouter.GET("/:username/posts/profile", handlers.GetUserProfilePosts)
-		userRouter.GET("/:username/posts/:_id/similar", handlers.GetUserSimilarPosts)
-		userRouter.GET("/:username/posts/tagged", handlers.GetUserTaggedPosts)
+		userRouter.GET("/:username", Authorizer(false), handlers.GetUser)
+		userRouter.GET("/:username/posts/profile", Authorizer(false), handlers.GetUserProfilePosts)
+		userRouter.GET("/:username/posts/:_id/similar", Authorizer(false), handlers.GetUserSimilarPosts)
+		userRouter.GET("/:username/posts/tagged", Authorizer(false), handlers.GetUserTaggedPosts)
+		userRouter.GET("/:username/highlights", Authorizer(false), handlers.GetUserHighlights)
 		userRouter.GET("/me/posts/home", Authorizer(true), handlers.GetUserHomePosts)
 		userRouter.GET("/me/posts/saved", Authorizer(true), handlers.GetUserSavedPosts)
+		userRouter.GET("/me/posts/archived", Authorizer(true), handlers.GetUserArchivedPosts)
+		userRouter.GET("/me/recently-deleted", Authorizer(true), handlers.GetRecentlyDeleted)
+		userRouter.GET("/me/blocked", Authorizer(true), handlers.GetBlockedUsers)
+		userRouter.GET("/me/suggestions", Authorizer(true), handlers.GetUserSuggestions)
+		userRouter.POST("/me/suggestions/:_id/dismiss", Authorizer(true), handlers.DismissSuggestion)
+		userRouter.PATCH("/me/privacy", Authorizer(true), handlers.UpdateUserPrivacy)
+		userRouter.POST("/me/deactivate", Authorizer(true), handlers.DeactivateUser)
+		userRouter.DELETE("/me", Authorizer(true), handlers.DeleteUser)
+		userRouter.POST("/me/export", Authorizer(true), handlers.RequestDataExport)
+		userRouter.GET("/me/comment-filters", Authorizer(true), handlers.GetCommentFilterSettings)
+		userRouter.PUT("/me/comment-filters", Authorizer(true), handlers.UpdateCommentFilterSettings)
+		userRouter.POST("/:_id/block", Authorizer(true), handlers.BlockUser)
+		userRouter.POST("/:_id/unblock", Authorizer(true), handlers.UnblockUser)
+		userRouter.GET("/me/collections", Authorizer(true), handlers.GetSavedCollections)
+		userRouter.POST("/me/collections", Authorizer(true), handlers.CreateSavedCollection)
+		userRouter.PATCH("/me/collections/:_id", Authorizer(true), handlers.UpdateSavedCollection)
+		userRouter.DELETE("/me/collections/:_id", Authorizer(true), handlers.DeleteSavedCollection)
+		userRouter.GET("/me/collections/:_id/posts", Authorizer(true), handlers.GetSavedCollectionPosts)
+		userRouter.POST("/me/collections/:_id/posts", Authorizer(true), handlers.AddPostToSavedCollection)
+		userRouter.DELETE("/me/collections/:_id/posts/:postId", Authorizer(true), handlers.RemovePostFromSavedCollection)
+		userRouter.POST("/me/highlights", Authorizer(true), handlers.CreateHighlight)
+		userRouter.PATCH("/me/highlights/:_id", Authorizer(true), handlers.UpdateHighlight)
+		userRouter.DELETE("/me/highlights/:_id", Authorizer(true), handlers.DeleteHighlight)
 	}
 
 	return router
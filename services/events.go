@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CommentEvent            = "comment"
+	NotificationEvent       = "notification"
+	NotificationsCountEvent = "notifications"
+	ReplyEvent              = "reply"
+
+	eventBufferSize   = 64
+	eventRetryTimeout = 2 * time.Second
+)
+
+type Event struct {
+	Name string      `json:"event"`
+	Data interface{} `json:"data"`
+}
+
+// EventSubscriber receives the events of a single client connection
+type EventSubscriber struct {
+	Events  chan Event
+	UserID  primitive.ObjectID
+	mutex   sync.RWMutex
+	postIds map[primitive.ObjectID]bool
+}
+
+func (subscriber *EventSubscriber) SubscribeToPost(postId primitive.ObjectID) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	subscriber.postIds[postId] = true
+}
+
+func (subscriber *EventSubscriber) UnsubscribeFromPost(postId primitive.ObjectID) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+	delete(subscriber.postIds, postId)
+}
+
+func (subscriber *EventSubscriber) isSubscribedToPost(postId interface{}) bool {
+	id, ok := postId.(primitive.ObjectID)
+	if !ok {
+		return false
+	}
+
+	subscriber.mutex.RLock()
+	defer subscriber.mutex.RUnlock()
+	return subscriber.postIds[id]
+}
+
+// send drops the event instead of blocking the change streams when the client is too slow
+func (subscriber *EventSubscriber) send(event Event) {
+	select {
+	case subscriber.Events <- event:
+	default:
+	}
+}
+
+// Every API instance watches the change streams itself, so events reach clients regardless of
+// the instance that handled the write
+type eventHub struct {
+	countUnread func(ctx context.Context, userId interface{}) (int64, error)
+	mutex       sync.RWMutex
+	once        sync.Once
+	subscribers map[*EventSubscriber]bool
+}
+
+var hub = newEventHub()
+
+func newEventHub() *eventHub {
+	return &eventHub{countUnread: CountUnreadNotifications, subscribers: map[*EventSubscriber]bool{}}
+}
+
+func SubscribeToEvents(userId primitive.ObjectID) *EventSubscriber {
+	hub.once.Do(hub.start)
+	return hub.subscribe(userId)
+}
+
+func UnsubscribeFromEvents(subscriber *EventSubscriber) {
+	hub.unsubscribe(subscriber)
+}
+
+func (hub *eventHub) subscribe(userId primitive.ObjectID) *EventSubscriber {
+	subscriber := &EventSubscriber{
+		Events:  make(chan Event, eventBufferSize),
+		UserID:  userId,
+		postIds: map[primitive.ObjectID]bool{},
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.subscribers[subscriber] = true
+	return subscriber
+}
+
+// unsubscribe closes the events of the subscriber. Events are only sent while holding the read lock, so a
+// subscriber is never sent to after it is closed
+func (hub *eventHub) unsubscribe(subscriber *EventSubscriber) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	delete(hub.subscribers, subscriber)
+	close(subscriber.Events)
+}
+
+func CountUnreadNotifications(ctx context.Context, userId interface{}) (int64, error) {
+	collection := GetMongoDBCollection(config.NotificationsCollection)
+	return collection.CountDocuments(ctx, bson.M{"userId": userId, "read": false})
+}
+
+func (hub *eventHub) start() {
+	// Hidden comments and replies are only visible to their author, so they are not broadcast
+	inserts := bson.A{bson.M{"$match": bson.M{"operationType": "insert", "fullDocument.isHidden": bson.M{"$ne": true}}}}
+	go hub.watch(config.CommentsCollection, inserts, hub.handlePostChild(CommentEvent))
+	go hub.watch(config.RepliesCollection, inserts, hub.handlePostChild(ReplyEvent))
+
+	upserts := bson.A{bson.M{"$match": bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}}}}}
+	go hub.watch(config.NotificationsCollection, upserts, hub.handleNotification)
+}
+
+// watch resumes the change stream after the last seen event whenever it fails
+func (hub *eventHub) watch(collectionName string, pipeline bson.A, handle func(change bson.M)) {
+	var resumeToken bson.Raw
+	for {
+		changeStreamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		if resumeToken != nil {
+			changeStreamOptions.SetResumeAfter(resumeToken)
+		}
+
+		ctx := context.Background()
+		stream, err := GetMongoDBCollection(collectionName).Watch(ctx, pipeline, changeStreamOptions)
+		if err != nil {
+			log.Println(err)
+			time.Sleep(eventRetryTimeout)
+			continue
+		}
+
+		for stream.Next(ctx) {
+			change := bson.M{}
+			if err := stream.Decode(&change); err == nil {
+				handle(change)
+			}
+			resumeToken = stream.ResumeToken()
+		}
+
+		if err := stream.Err(); err != nil {
+			log.Println(err)
+		}
+		stream.Close(ctx)
+		time.Sleep(eventRetryTimeout)
+	}
+}
+
+func (hub *eventHub) handlePostChild(eventName string) func(change bson.M) {
+	return func(change bson.M) {
+		document, ok := change["fullDocument"].(bson.M)
+		if !ok {
+			return
+		}
+
+		hub.mutex.RLock()
+		defer hub.mutex.RUnlock()
+		for subscriber := range hub.subscribers {
+			if subscriber.isSubscribedToPost(document["postId"]) {
+				subscriber.send(Event{Name: eventName, Data: document})
+			}
+		}
+	}
+}
+
+// handleNotification counts the unread notifications before taking the lock, so a slow count does not hold up
+// clients connecting or disconnecting
+func (hub *eventHub) handleNotification(change bson.M) {
+	document, ok := change["fullDocument"].(bson.M)
+	if !ok {
+		return
+	}
+
+	userId := document["userId"]
+	if !hub.hasUserSubscribers(userId) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := []Event{}
+	if hasNewActor(change) {
+		events = append(events, Event{Name: NotificationEvent, Data: bson.M{
+			"_id":      document["_id"],
+			"actorId":  lastActorId(document),
+			"entityId": document["entityId"],
+			"type":     document["type"],
+		}})
+	}
+
+	unreadCount, err := hub.countUnread(ctx, userId)
+	if err != nil {
+		log.Println(err)
+	} else {
+		events = append(events, Event{Name: NotificationsCountEvent, Data: bson.M{"unreadCount": unreadCount}})
+	}
+
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+	for subscriber := range hub.subscribers {
+		if subscriber.UserID != userId {
+			continue
+		}
+
+		for _, event := range events {
+			subscriber.send(event)
+		}
+	}
+}
+
+func (hub *eventHub) hasUserSubscribers(userId interface{}) bool {
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+
+	for subscriber := range hub.subscribers {
+		if subscriber.UserID == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNewActor(change bson.M) bool {
+	if change["operationType"] != "update" {
+		return true
+	}
+
+	description, _ := change["updateDescription"].(bson.M)
+	updatedFields, _ := description["updatedFields"].(bson.M)
+	for field := range updatedFields {
+		if strings.HasPrefix(field, "actorIds") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lastActorId(document bson.M) interface{} {
+	actorIds, ok := document["actorIds"].(bson.A)
+	if !ok || len(actorIds) == 0 {
+		return nil
+	}
+
+	return actorIds[len(actorIds)-1]
+}
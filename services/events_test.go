@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestEventHub(count int64) *eventHub {
+	hub := newEventHub()
+	hub.countUnread = func(ctx context.Context, userId interface{}) (int64, error) {
+		return count, nil
+	}
+	return hub
+}
+
+func receive(subscriber *EventSubscriber) []Event {
+	events := []Event{}
+	for {
+		select {
+		case event := <-subscriber.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestHandlePostChildOnlyReachesPostSubscribers(t *testing.T) {
+	hub := newTestEventHub(0)
+	postId := primitive.NewObjectID()
+	subscribed := hub.subscribe(primitive.NewObjectID())
+	subscribed.SubscribeToPost(postId)
+	other := hub.subscribe(primitive.NewObjectID())
+
+	hub.handlePostChild(CommentEvent)(bson.M{"fullDocument": bson.M{"postId": postId}})
+
+	events := receive(subscribed)
+	if len(events) != 1 || events[0].Name != CommentEvent {
+		t.Errorf("subscribed events = %v, want one %v event", events, CommentEvent)
+	}
+
+	if events := receive(other); len(events) != 0 {
+		t.Errorf("other events = %v, want none", events)
+	}
+
+	subscribed.UnsubscribeFromPost(postId)
+	hub.handlePostChild(CommentEvent)(bson.M{"fullDocument": bson.M{"postId": postId}})
+	if events := receive(subscribed); len(events) != 0 {
+		t.Errorf("events after unsubscribing from post = %v, want none", events)
+	}
+}
+
+func TestHandleNotificationOnlyReachesItsUser(t *testing.T) {
+	hub := newTestEventHub(3)
+	userId := primitive.NewObjectID()
+	actorId := primitive.NewObjectID()
+	subscriber := hub.subscribe(userId)
+	other := hub.subscribe(primitive.NewObjectID())
+
+	hub.handleNotification(bson.M{
+		"operationType": "insert",
+		"fullDocument":  bson.M{"_id": primitive.NewObjectID(), "actorIds": bson.A{actorId}, "userId": userId},
+	})
+
+	events := receive(subscriber)
+	if len(events) != 2 {
+		t.Fatalf("events = %v, want a notification and a count", events)
+	}
+
+	if events[0].Name != NotificationEvent || events[0].Data.(bson.M)["actorId"] != actorId {
+		t.Errorf("first event = %v, want a notification by %v", events[0], actorId)
+	}
+
+	if events[1].Name != NotificationsCountEvent || events[1].Data.(bson.M)["unreadCount"] != int64(3) {
+		t.Errorf("second event = %v, want an unread count of 3", events[1])
+	}
+
+	if events := receive(other); len(events) != 0 {
+		t.Errorf("other events = %v, want none", events)
+	}
+}
+
+func TestHandleNotificationOnlySendsCountWhenReadStateChanges(t *testing.T) {
+	hub := newTestEventHub(0)
+	userId := primitive.NewObjectID()
+	subscriber := hub.subscribe(userId)
+
+	hub.handleNotification(bson.M{
+		"operationType":     "update",
+		"fullDocument":      bson.M{"_id": primitive.NewObjectID(), "userId": userId},
+		"updateDescription": bson.M{"updatedFields": bson.M{"read": true}},
+	})
+
+	events := receive(subscriber)
+	if len(events) != 1 || events[0].Name != NotificationsCountEvent {
+		t.Errorf("events = %v, want only a count", events)
+	}
+}
+
+func TestSendDropsEventsWhenBufferIsFull(t *testing.T) {
+	hub := newTestEventHub(0)
+	postId := primitive.NewObjectID()
+	subscriber := hub.subscribe(primitive.NewObjectID())
+	subscriber.SubscribeToPost(postId)
+
+	for i := 0; i < eventBufferSize+1; i++ {
+		hub.handlePostChild(ReplyEvent)(bson.M{"fullDocument": bson.M{"postId": postId}})
+	}
+
+	if events := receive(subscriber); len(events) != eventBufferSize {
+		t.Errorf("received %v events, want %v", len(events), eventBufferSize)
+	}
+}
+
+// Unsubscribing while a slow count is in flight used to send on the closed channel and panic
+func TestUnsubscribeDuringNotificationDoesNotPanic(t *testing.T) {
+	hub := newEventHub()
+	counting := make(chan struct{})
+	hub.countUnread = func(ctx context.Context, userId interface{}) (int64, error) {
+		close(counting)
+		time.Sleep(20 * time.Millisecond)
+		return 1, nil
+	}
+
+	userId := primitive.NewObjectID()
+	subscriber := hub.subscribe(userId)
+
+	wait := sync.WaitGroup{}
+	wait.Add(1)
+	go func() {
+		defer wait.Done()
+		hub.handleNotification(bson.M{"operationType": "insert", "fullDocument": bson.M{"userId": userId}})
+	}()
+
+	<-counting
+	hub.unsubscribe(subscriber)
+	wait.Wait()
+}
+
+func TestConcurrentSubscriptionsAndEvents(t *testing.T) {
+	hub := newTestEventHub(1)
+	userId := primitive.NewObjectID()
+	postId := primitive.NewObjectID()
+
+	wait := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wait.Add(2)
+		go func() {
+			defer wait.Done()
+			subscriber := hub.subscribe(userId)
+			subscriber.SubscribeToPost(postId)
+			hub.unsubscribe(subscriber)
+		}()
+		go func() {
+			defer wait.Done()
+			hub.handlePostChild(CommentEvent)(bson.M{"fullDocument": bson.M{"postId": postId}})
+			hub.handleNotification(bson.M{"operationType": "insert", "fullDocument": bson.M{"userId": userId}})
+		}()
+	}
+	wait.Wait()
+}
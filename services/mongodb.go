@@ -50,6 +50,17 @@ func CreateMongoDBIndexes(ctx context.Context) ([]string, error) {
 		{
 			Keys:    bsonx.Doc{{Key: "username", Value: bsonx.Int32(1)}},
 			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bsonx.Doc{{Key: "followersCount", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+		},
+		{
+			Keys:    bsonx.Doc{{Key: "deactivatedAt", Value: bsonx.Int32(1)}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"deactivatedAt": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bsonx.Doc{{Key: "deletionRequestedAt", Value: bsonx.Int32(1)}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"deletionRequestedAt": bson.M{"$exists": true}}),
 		}}
 	usersCollection := GetMongoDBCollection(config.UsersCollection)
 	userIndexes, err := usersCollection.Indexes().CreateMany(ctx, userModels)
@@ -62,6 +73,7 @@ func CreateMongoDBIndexes(ctx context.Context) ([]string, error) {
 	}, {
 		Keys: bsonx.Doc{{Key: "caption", Value: bsonx.String("text")}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
 	}}
+	postModels = append(postModels, deletedContentIndexModels()...)
 	postsCollection := GetMongoDBCollection(config.PostsCollection)
 	postIndexes, err := postsCollection.Indexes().CreateMany(ctx, postModels)
 	if err != nil {
@@ -70,6 +82,10 @@ func CreateMongoDBIndexes(ctx context.Context) ([]string, error) {
 
 	userDetailModels := []mongo.IndexModel{{
 		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
+	}, {
+		// A post can only be in one of the saved posts buckets of a user
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "savedPosts", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"savedPostsCount": bson.M{"$gt": 0}}),
 	}}
 	userDetailsCollection := GetMongoDBCollection(config.UserDetailsCollection)
 	userDetailIndexes, err := userDetailsCollection.Indexes().CreateMany(ctx, userDetailModels)
@@ -79,7 +95,10 @@ func CreateMongoDBIndexes(ctx context.Context) ([]string, error) {
 
 	commentModels := []mongo.IndexModel{{
 		Keys: bsonx.Doc{{Key: "postId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}},
 	}}
+	commentModels = append(commentModels, deletedContentIndexModels()...)
 	commentsCollection := GetMongoDBCollection(config.CommentsCollection)
 	commentIndexes, err := commentsCollection.Indexes().CreateMany(ctx, commentModels)
 	if err != nil {
@@ -90,17 +109,237 @@ func CreateMongoDBIndexes(ctx context.Context) ([]string, error) {
 		Keys: bsonx.Doc{{Key: "postId", Value: bsonx.Int32(1)}},
 	}, {
 		Keys: bsonx.Doc{{Key: "replyToId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}},
 	}}
+	replyModels = append(replyModels, deletedContentIndexModels()...)
 	repliesCollection := GetMongoDBCollection(config.RepliesCollection)
 	replyIndexes, err := repliesCollection.Indexes().CreateMany(ctx, replyModels)
 	if err != nil {
 		return nil, err
 	}
 
+	savedCollectionModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "name", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
+	}}
+	savedCollectionsCollection := GetMongoDBCollection(config.SavedCollectionsCollection)
+	savedCollectionIndexes, err := savedCollectionsCollection.Indexes().CreateMany(ctx, savedCollectionModels)
+	if err != nil {
+		return nil, err
+	}
+
+	savedCollectionPostModels := []mongo.IndexModel{{
+		Keys: bsonx.Doc{{Key: "collectionId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys:    bsonx.Doc{{Key: "collectionId", Value: bsonx.Int32(1)}, {Key: "posts", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"postsCount": bson.M{"$gt": 0}}),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "posts", Value: bsonx.Int32(1)}},
+	}}
+	savedCollectionPostsCollection := GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	savedCollectionPostIndexes, err := savedCollectionPostsCollection.Indexes().CreateMany(ctx, savedCollectionPostModels)
+	if err != nil {
+		return nil, err
+	}
+
+	blockModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "blockerId", Value: bsonx.Int32(1)}, {Key: "userId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "blockerId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}},
+	}}
+	blocksCollection := GetMongoDBCollection(config.BlocksCollection)
+	blockIndexes, err := blocksCollection.Indexes().CreateMany(ctx, blockModels)
+	if err != nil {
+		return nil, err
+	}
+
+	closeFriendModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "friendId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "friendId", Value: bsonx.Int32(1)}},
+	}}
+	closeFriendsCollection := GetMongoDBCollection(config.CloseFriendsCollection)
+	closeFriendIndexes, err := closeFriendsCollection.Indexes().CreateMany(ctx, closeFriendModels)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationModels := []mongo.IndexModel{{
+		Keys: bsonx.Doc{{Key: "members.userId", Value: bsonx.Int32(1)}, {Key: "lastMessageAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "memberIds", Value: bsonx.Int32(1)}},
+	}}
+	conversationsCollection := GetMongoDBCollection(config.ConversationsCollection)
+	conversationIndexes, err := conversationsCollection.Indexes().CreateMany(ctx, conversationModels)
+	if err != nil {
+		return nil, err
+	}
+
+	followRequestModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "requesterId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}}
+	followRequestsCollection := GetMongoDBCollection(config.FollowRequestsCollection)
+	followRequestIndexes, err := followRequestsCollection.Indexes().CreateMany(ctx, followRequestModels)
+	if err != nil {
+		return nil, err
+	}
+
+	dismissedSuggestionModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "dismissedUserId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}}
+	dismissedSuggestionsCollection := GetMongoDBCollection(config.DismissedSuggestionsCollection)
+	dismissedSuggestionIndexes, err := dismissedSuggestionsCollection.Indexes().CreateMany(ctx, dismissedSuggestionModels)
+	if err != nil {
+		return nil, err
+	}
+
+	followModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "followerId", Value: bsonx.Int32(1)}, {Key: "followeeId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "followeeId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "followerId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}}
+	followsCollection := GetMongoDBCollection(config.FollowsCollection)
+	followIndexes, err := followsCollection.Indexes().CreateMany(ctx, followModels)
+	if err != nil {
+		return nil, err
+	}
+
+	messageModels := []mongo.IndexModel{{
+		Keys: bsonx.Doc{{Key: "conversationId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}}
+	messagesCollection := GetMongoDBCollection(config.MessagesCollection)
+	messageIndexes, err := messagesCollection.Indexes().CreateMany(ctx, messageModels)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "type", Value: bsonx.Int32(1)}, {Key: "entityId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"read": false}),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "read", Value: bsonx.Int32(1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "actorIds", Value: bsonx.Int32(1)}},
+	}}
+	notificationsCollection := GetMongoDBCollection(config.NotificationsCollection)
+	notificationIndexes, err := notificationsCollection.Indexes().CreateMany(ctx, notificationModels)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExportModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"status": "pending"}),
+	}, {
+		Keys: bsonx.Doc{{Key: "status", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "status", Value: bsonx.Int32(1)}, {Key: "completedAt", Value: bsonx.Int32(1)}},
+	}}
+	dataExportsCollection := GetMongoDBCollection(config.DataExportsCollection)
+	dataExportIndexes, err := dataExportsCollection.Indexes().CreateMany(ctx, dataExportModels)
+	if err != nil {
+		return nil, err
+	}
+
+	highlightModels := []mongo.IndexModel{{
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}}
+	highlightsCollection := GetMongoDBCollection(config.HighlightsCollection)
+	highlightIndexes, err := highlightsCollection.Indexes().CreateMany(ctx, highlightModels)
+	if err != nil {
+		return nil, err
+	}
+
+	storyModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "expiresAt", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "expiresAt", Value: bsonx.Int32(1)}},
+	}}
+	storiesCollection := GetMongoDBCollection(config.StoriesCollection)
+	storyIndexes, err := storiesCollection.Indexes().CreateMany(ctx, storyModels)
+	if err != nil {
+		return nil, err
+	}
+
+	storyArchiveModels := []mongo.IndexModel{{
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}}
+	storyArchiveCollection := GetMongoDBCollection(config.StoryArchiveCollection)
+	storyArchiveIndexes, err := storyArchiveCollection.Indexes().CreateMany(ctx, storyArchiveModels)
+	if err != nil {
+		return nil, err
+	}
+
+	storyViewModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "storyId", Value: bsonx.Int32(1)}, {Key: "viewerId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "storyId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys:    bsonx.Doc{{Key: "expiresAt", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}}
+	storyViewsCollection := GetMongoDBCollection(config.StoryViewsCollection)
+	storyViewIndexes, err := storyViewsCollection.Indexes().CreateMany(ctx, storyViewModels)
+	if err != nil {
+		return nil, err
+	}
+
+	timelineModels := []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "postId", Value: bsonx.Int32(1)}},
+		Options: options.Index().SetUnique(true),
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "createdAt", Value: bsonx.Int32(-1)}, {Key: "postId", Value: bsonx.Int32(-1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "userId", Value: bsonx.Int32(1)}, {Key: "authorId", Value: bsonx.Int32(1)}},
+	}, {
+		Keys: bsonx.Doc{{Key: "postId", Value: bsonx.Int32(1)}},
+	}}
+	timelinesCollection := GetMongoDBCollection(config.TimelinesCollection)
+	timelineIndexes, err := timelinesCollection.Indexes().CreateMany(ctx, timelineModels)
+	if err != nil {
+		return nil, err
+	}
+
 	indexes := append(userIndexes, postIndexes...)
 	indexes = append(indexes, userDetailIndexes...)
 	indexes = append(indexes, commentIndexes...)
 	indexes = append(indexes, replyIndexes...)
+	indexes = append(indexes, savedCollectionIndexes...)
+	indexes = append(indexes, savedCollectionPostIndexes...)
+	indexes = append(indexes, blockIndexes...)
+	indexes = append(indexes, closeFriendIndexes...)
+	indexes = append(indexes, conversationIndexes...)
+	indexes = append(indexes, dataExportIndexes...)
+	indexes = append(indexes, dismissedSuggestionIndexes...)
+	indexes = append(indexes, followRequestIndexes...)
+	indexes = append(indexes, followIndexes...)
+	indexes = append(indexes, highlightIndexes...)
+	indexes = append(indexes, messageIndexes...)
+	indexes = append(indexes, notificationIndexes...)
+	indexes = append(indexes, storyIndexes...)
+	indexes = append(indexes, storyArchiveIndexes...)
+	indexes = append(indexes, storyViewIndexes...)
+	indexes = append(indexes, timelineIndexes...)
 	return indexes, nil
 }
 
@@ -112,13 +351,65 @@ func GetMongoDBClient() *mongo.Client {
 	return mongoClient
 }
 
+// deletedContentIndexModels only cover soft deleted documents. They back the recently deleted listing and the purge
+func deletedContentIndexModels() []mongo.IndexModel {
+	deleted := options.Index().SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$exists": true}})
+	return []mongo.IndexModel{{
+		Keys:    bsonx.Doc{{Key: "deletedBy", Value: bsonx.Int32(1)}, {Key: "deletedAt", Value: bsonx.Int32(-1)}, {Key: "_id", Value: bsonx.Int32(-1)}},
+		Options: deleted,
+	}, {
+		Keys:    bsonx.Doc{{Key: "deletedAt", Value: bsonx.Int32(1)}},
+		Options: deleted,
+	}}
+}
+
 func GetMongoDBCollection(name string, opts ...*options.CollectionOptions) *mongo.Collection {
 	return mongoClient.Database(config.MongoDBName).Collection(name, opts...)
 }
 
 func DeleteAllMongoDBDocuments() {
+	blocksCollection := GetMongoDBCollection(config.BlocksCollection)
+	_, err := blocksCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	closeFriendsCollection := GetMongoDBCollection(config.CloseFriendsCollection)
+	_, err = closeFriendsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
 	commentsCollection := GetMongoDBCollection(config.CommentsCollection)
-	_, err := commentsCollection.DeleteMany(context.Background(), bson.M{})
+	_, err = commentsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	conversationsCollection := GetMongoDBCollection(config.ConversationsCollection)
+	_, err = conversationsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	dataExportsCollection := GetMongoDBCollection(config.DataExportsCollection)
+	_, err = dataExportsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	dismissedSuggestionsCollection := GetMongoDBCollection(config.DismissedSuggestionsCollection)
+	_, err = dismissedSuggestionsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	followRequestsCollection := GetMongoDBCollection(config.FollowRequestsCollection)
+	_, err = followRequestsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	followsCollection := GetMongoDBCollection(config.FollowsCollection)
+	_, err = followsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	highlightsCollection := GetMongoDBCollection(config.HighlightsCollection)
+	_, err = highlightsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	messagesCollection := GetMongoDBCollection(config.MessagesCollection)
+	_, err = messagesCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	notificationsCollection := GetMongoDBCollection(config.NotificationsCollection)
+	_, err = notificationsCollection.DeleteMany(context.Background(), bson.M{})
 	helpers.ExitIfError(err)
 
 	postsCollection := GetMongoDBCollection(config.PostsCollection)
@@ -129,6 +420,30 @@ func DeleteAllMongoDBDocuments() {
 	_, err = repliesCollection.DeleteMany(context.Background(), bson.M{})
 	helpers.ExitIfError(err)
 
+	savedCollectionsCollection := GetMongoDBCollection(config.SavedCollectionsCollection)
+	_, err = savedCollectionsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	savedCollectionPostsCollection := GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	_, err = savedCollectionPostsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	storiesCollection := GetMongoDBCollection(config.StoriesCollection)
+	_, err = storiesCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	storyArchiveCollection := GetMongoDBCollection(config.StoryArchiveCollection)
+	_, err = storyArchiveCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	storyViewsCollection := GetMongoDBCollection(config.StoryViewsCollection)
+	_, err = storyViewsCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
+	timelinesCollection := GetMongoDBCollection(config.TimelinesCollection)
+	_, err = timelinesCollection.DeleteMany(context.Background(), bson.M{})
+	helpers.ExitIfError(err)
+
 	usersCollection := GetMongoDBCollection(config.UsersCollection)
 	_, err = usersCollection.DeleteMany(context.Background(), bson.M{})
 	helpers.ExitIfError(err)
@@ -1,16 +1,22 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// S3 deletes at most this many objects per request
+const maxDeleteObjectsKeys = 1000
+
 type PresignedURLOption struct {
 	Keys []string
 }
@@ -49,3 +55,89 @@ func GeneratePresignedURLs(keys []string) ([]string, error) {
 
 	return urls, nil
 }
+
+// GeneratePresignedDownloadURL returns a URL anyone can download the object from until it expires
+func GeneratePresignedDownloadURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	presignClient := s3.NewPresignClient(s3.NewFromConfig(cfg), func(options *s3.PresignOptions) {
+		options.Expires = expires
+	})
+	presignedRequest, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("AWS_BUCKET")),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return presignedRequest.URL, nil
+}
+
+// UploadObject stores body under key through the same presigned URLs the clients upload images to
+func UploadObject(ctx context.Context, key string, body []byte) error {
+	urls, err := GeneratePresignedURLs([]string{key})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, urls[0], bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not upload %v: %v", key, response.Status)
+	}
+
+	return nil
+}
+
+func DeleteObjects(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := s3.NewFromConfig(cfg)
+	for start := 0; start < len(keys); start += maxDeleteObjectsKeys {
+		end := start + maxDeleteObjectsKeys
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		output, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(os.Getenv("AWS_BUCKET")),
+			Delete: &types.Delete{Objects: objects, Quiet: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Keys that could not be deleted are reported in a successful response
+		if len(output.Errors) > 0 {
+			failure := output.Errors[0]
+			return fmt.Errorf("could not delete %v objects, e.g %v: %v", len(output.Errors), aws.ToString(failure.Key), aws.ToString(failure.Message))
+		}
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
@@ -94,6 +95,40 @@ func (suite *LoginTestSuite) Test_Succeeds() {
 	suite.Contains(response.Result().Header, "Set-Cookie")
 }
 
+func (suite *LoginTestSuite) Test_SucceedsAndReactivatesDeactivatedAccount() {
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"email": suite.Email}, bson.M{"$set": bson.M{"deactivatedAt": time.Now()}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"email": suite.Email, "deactivatedAt": nil}).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, response.Code)
+}
+
+func (suite *LoginTestSuite) Test_FailsIfAccountIsBeingDeleted() {
+	update := bson.M{"$set": bson.M{"deactivatedAt": time.Now(), "deletionRequestedAt": time.Now()}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"email": suite.Email}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+	suite.NotContains(response.Result().Header, "Set-Cookie")
+}
+
 func (suite *LoginTestSuite) Test_FailsWithInvalidInputs() {
 	suite.Email = strings.Join(make([]string, 247), "a") + "@gmail.com"
 	suite.Password = "111"
@@ -0,0 +1,347 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SavedCollectionsTestSuite struct {
+	suite.Suite
+	CollectionID                   primitive.ObjectID
+	EmptyCollectionID              primitive.ObjectID
+	OtherCollectionID              primitive.ObjectID
+	PostID                         primitive.ObjectID
+	PostsCollection                *mongo.Collection
+	ResponseBody                   bson.M
+	SavedCollectionPostsCollection *mongo.Collection
+	SavedCollectionsCollection     *mongo.Collection
+	SavedPostID                    primitive.ObjectID
+	Token                          string
+	UserDetailsCollection          *mongo.Collection
+	UserID                         primitive.ObjectID
+	UsersCollection                *mongo.Collection
+}
+
+func (suite *SavedCollectionsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.SavedCollectionPostsCollection = services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	suite.SavedCollectionsCollection = services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	suite.UserDetailsCollection = services.GetMongoDBCollection(config.UserDetailsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *SavedCollectionsTestSuite) SetupTest() {
+	result, err := mocks.SavedCollections()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CollectionID = result.CollectionID
+	suite.EmptyCollectionID = result.EmptyCollectionID
+	suite.OtherCollectionID = result.OtherCollectionID
+	suite.PostID = result.PostID
+	suite.ResponseBody = bson.M{}
+	suite.SavedPostID = result.SavedPostID
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+}
+
+func (suite *SavedCollectionsTestSuite) ExecuteRequest(method, path string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, "/users/me/collections"+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *SavedCollectionsTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{
+		suite.PostsCollection,
+		suite.SavedCollectionPostsCollection,
+		suite.SavedCollectionsCollection,
+		suite.UserDetailsCollection,
+		suite.UsersCollection,
+	}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *SavedCollectionsTestSuite) FindCollection(collectionId primitive.ObjectID) *models.SavedCollection {
+	collection := &models.SavedCollection{}
+	err := suite.SavedCollectionsCollection.FindOne(context.Background(), bson.M{"_id": collectionId}).Decode(collection)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return collection
+}
+
+func (suite *SavedCollectionsTestSuite) IsSaved(postId primitive.ObjectID) bool {
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "savedPosts": postId})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return count == 1
+}
+
+func (suite *SavedCollectionsTestSuite) CountCollectionPosts(collectionId, postId primitive.ObjectID) int64 {
+	count, err := suite.SavedCollectionPostsCollection.CountDocuments(context.Background(), bson.M{"collectionId": collectionId, "posts": postId})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return count
+}
+
+func (suite *SavedCollectionsTestSuite) ItemIds() []string {
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+
+	ids := []string{}
+	for _, item := range items {
+		document, _ := item.(map[string]interface{})
+		ids = append(ids, document["_id"].(string))
+	}
+
+	return ids
+}
+
+func (suite *SavedCollectionsTestSuite) Test_CreateSucceeds() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "", bson.M{"name": " Recipes "})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusCreated, response.Code)
+	collection, _ := suite.ResponseBody["collection"].(map[string]interface{})
+	suite.Equal("Recipes", collection["name"])
+
+	count, err := suite.SavedCollectionsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "name": "Recipes"})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_CreateFailsIfNameIsTaken() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "", bson.M{"name": "Favorites"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *SavedCollectionsTestSuite) Test_CreateFailsIfNameIsMissing() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "", bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_GetReturnsOnlyTheCollectionsOfTheUser() {
+	response, err := suite.ExecuteRequest(http.MethodGet, "", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.ElementsMatch([]string{suite.CollectionID.Hex(), suite.EmptyCollectionID.Hex()}, suite.ItemIds())
+}
+
+func (suite *SavedCollectionsTestSuite) Test_UpdateRenamesTheCollection() {
+	response, err := suite.ExecuteRequest(http.MethodPatch, "/"+suite.EmptyCollectionID.Hex(), bson.M{"name": "Someday"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("Someday", suite.FindCollection(suite.EmptyCollectionID).Name)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_UpdateFailsIfNameIsTaken() {
+	response, err := suite.ExecuteRequest(http.MethodPatch, "/"+suite.EmptyCollectionID.Hex(), bson.M{"name": "Favorites"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Equal("Later", suite.FindCollection(suite.EmptyCollectionID).Name)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_DeleteKeepsThePostsSaved() {
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/"+suite.CollectionID.Hex(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	count, err := suite.SavedCollectionsCollection.CountDocuments(context.Background(), bson.M{"_id": suite.CollectionID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+	suite.Equal(int64(0), suite.CountCollectionPosts(suite.CollectionID, suite.SavedPostID))
+	suite.True(suite.IsSaved(suite.SavedPostID))
+}
+
+func (suite *SavedCollectionsTestSuite) Test_AddPostAlsoSavesIt() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest(http.MethodPost, "/"+suite.EmptyCollectionID.Hex()+"/posts", bson.M{"postId": suite.PostID.Hex()})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	suite.True(suite.IsSaved(suite.PostID))
+	suite.Equal(int64(1), suite.CountCollectionPosts(suite.EmptyCollectionID, suite.PostID))
+	suite.Equal(1, suite.FindCollection(suite.EmptyCollectionID).PostsCount)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_AddPostFailsIfPostNotFound() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "/"+suite.EmptyCollectionID.Hex()+"/posts", bson.M{"postId": primitive.NewObjectID().Hex()})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *SavedCollectionsTestSuite) Test_AddPostFailsIfPostIsArchived() {
+	_, err := suite.PostsCollection.UpdateByID(context.Background(), suite.PostID, bson.M{"$set": bson.M{"archivedAt": time.Now()}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPost, "/"+suite.EmptyCollectionID.Hex()+"/posts", bson.M{"postId": suite.PostID.Hex()})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.False(suite.IsSaved(suite.PostID))
+	suite.Equal(int64(0), suite.CountCollectionPosts(suite.EmptyCollectionID, suite.PostID))
+}
+
+func (suite *SavedCollectionsTestSuite) Test_RemovePostKeepsItSaved() {
+	path := "/" + suite.CollectionID.Hex() + "/posts/" + suite.SavedPostID.Hex()
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest(http.MethodDelete, path, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	suite.Equal(int64(0), suite.CountCollectionPosts(suite.CollectionID, suite.SavedPostID))
+	suite.Equal(0, suite.FindCollection(suite.CollectionID).PostsCount)
+	suite.True(suite.IsSaved(suite.SavedPostID))
+}
+
+func (suite *SavedCollectionsTestSuite) Test_GetPostsReturnsThePostsOfTheCollection() {
+	response, err := suite.ExecuteRequest(http.MethodGet, "/"+suite.CollectionID.Hex()+"/posts", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal([]string{suite.SavedPostID.Hex()}, suite.ItemIds())
+}
+
+func (suite *SavedCollectionsTestSuite) Test_FailsIfCollectionBelongsToAnotherUser() {
+	path := "/" + suite.OtherCollectionID.Hex()
+	requests := []struct {
+		method string
+		path   string
+		body   bson.M
+	}{
+		{http.MethodPatch, path, bson.M{"name": "Mine"}},
+		{http.MethodDelete, path, nil},
+		{http.MethodGet, path + "/posts", nil},
+		{http.MethodPost, path + "/posts", bson.M{"postId": suite.PostID.Hex()}},
+		{http.MethodDelete, path + "/posts/" + suite.PostID.Hex(), nil},
+	}
+	for _, request := range requests {
+		response, err := suite.ExecuteRequest(request.method, request.path, request.body)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusNotFound, response.Code, request.method+" "+request.path)
+	}
+
+	collection := suite.FindCollection(suite.OtherCollectionID)
+	suite.Equal("Favorites", collection.Name)
+	suite.Equal(0, collection.PostsCount)
+}
+
+func (suite *SavedCollectionsTestSuite) Test_FailsIfCollectionIdIsInvalid() {
+	for _, method := range []string{http.MethodPatch, http.MethodDelete} {
+		response, err := suite.ExecuteRequest(method, "/invalid", bson.M{"name": "Mine"})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+		suite.Contains(suite.ResponseBody, "message")
+	}
+}
+
+func (suite *SavedCollectionsTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest(http.MethodGet, "", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestSavedCollectionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SavedCollectionsTestSuite))
+}
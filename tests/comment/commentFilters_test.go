@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CommentFiltersTestSuite struct {
+	suite.Suite
+	Collections   []*mongo.Collection
+	CommentIDs    []primitive.ObjectID
+	FollowerToken string
+	OwnerToken    string
+	PostID        primitive.ObjectID
+	ResponseBody  bson.M
+	StrangerToken string
+}
+
+func (suite *CommentFiltersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	collectionNames := []string{
+		config.CommentsCollection,
+		config.FollowsCollection,
+		config.NotificationsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collectionNames {
+		suite.Collections = append(suite.Collections, services.GetMongoDBCollection(name))
+	}
+}
+
+func (suite *CommentFiltersTestSuite) SetupTest() {
+	result, err := mocks.ModerateComments()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentIDs = result.CommentIDs
+	suite.FollowerToken = result.FollowerToken
+	suite.OwnerToken = result.OwnerToken
+	suite.PostID = result.PostID
+	suite.ResponseBody = bson.M{}
+	suite.StrangerToken = result.StrangerToken
+
+	hideOffensive := false
+	response, err := suite.ExecuteRequest(http.MethodPut, "/users/me/comment-filters", suite.OwnerToken, bson.M{"hideOffensive": hideOffensive, "keywords": bson.A{" Spoiler "}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if response.Code != http.StatusOK {
+		log.Fatalf("could not update the comment filters: %v", suite.ResponseBody)
+	}
+}
+
+func (suite *CommentFiltersTestSuite) ExecuteRequest(method, path, token string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *CommentFiltersTestSuite) TearDownTest() {
+	for _, collection := range suite.Collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// Create posts a comment, or a reply to replyToId when it is set, and returns its id
+func (suite *CommentFiltersTestSuite) Create(token, message string, replyToId *primitive.ObjectID) string {
+	path, name := "/comments", "comment"
+	requestBody := bson.M{"message": message, "postId": suite.PostID.Hex()}
+	if replyToId != nil {
+		path, name = "/replies", "reply"
+		requestBody["replyToId"] = replyToId.Hex()
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPost, path, token, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusCreated, response.Code)
+	document, _ := suite.ResponseBody[name].(map[string]interface{})
+	id, _ := document["_id"].(string)
+	return id
+}
+
+func (suite *CommentFiltersTestSuite) ListIds(path, token string) []string {
+	response, err := suite.ExecuteRequest(http.MethodGet, path, token, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	ids := []string{}
+	for _, item := range items {
+		document, _ := item.(map[string]interface{})
+		ids = append(ids, document["_id"].(string))
+	}
+
+	return ids
+}
+
+func (suite *CommentFiltersTestSuite) IsHidden(collectionName, id string) bool {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	document := bson.M{}
+	err = services.GetMongoDBCollection(collectionName).FindOne(context.Background(), bson.M{"_id": objectId}).Decode(&document)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return document["isHidden"] == true
+}
+
+func (suite *CommentFiltersTestSuite) Test_MatchingCommentsAreHiddenFromEveryoneButTheirAuthor() {
+	hiddenId := suite.Create(suite.FollowerToken, "Big SPOILER, the butler did it!", nil)
+	visibleId := suite.Create(suite.FollowerToken, "Spoilers are fine", nil)
+	suite.True(suite.IsHidden(config.CommentsCollection, hiddenId))
+	suite.False(suite.IsHidden(config.CommentsCollection, visibleId))
+
+	commentsPath := fmt.Sprintf("/comments?postId=%v", suite.PostID.Hex())
+	strangerIds := suite.ListIds(commentsPath, suite.StrangerToken)
+	suite.NotContains(strangerIds, hiddenId)
+	suite.Contains(strangerIds, visibleId)
+	suite.Contains(suite.ListIds(commentsPath, suite.FollowerToken), hiddenId)
+
+	post := &models.Post{}
+	err := services.GetMongoDBCollection(config.PostsCollection).FindOne(context.Background(), bson.M{"_id": suite.PostID}).Decode(post)
+	suite.NoError(err)
+	previewIds := []string{}
+	for _, comment := range post.Comments {
+		previewIds = append(previewIds, comment.ID.Hex())
+	}
+	suite.NotContains(previewIds, hiddenId)
+	suite.Contains(previewIds, visibleId)
+
+	hiddenPath := fmt.Sprintf("/comments/hidden?postId=%v", suite.PostID.Hex())
+	suite.Equal([]string{hiddenId}, suite.ListIds(hiddenPath, suite.OwnerToken))
+
+	response, err := suite.ExecuteRequest(http.MethodGet, hiddenPath, suite.FollowerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+}
+
+func (suite *CommentFiltersTestSuite) Test_MatchingRepliesAreHiddenFromEveryoneButTheirAuthor() {
+	hiddenId := suite.Create(suite.FollowerToken, "spoiler: the butler", &suite.CommentIDs[0])
+	visibleId := suite.Create(suite.StrangerToken, "No spoilers here", &suite.CommentIDs[0])
+	suite.True(suite.IsHidden(config.RepliesCollection, hiddenId))
+	suite.False(suite.IsHidden(config.RepliesCollection, visibleId))
+
+	repliesPath := fmt.Sprintf("/replies?replyToId=%v", suite.CommentIDs[0].Hex())
+	strangerIds := suite.ListIds(repliesPath, suite.StrangerToken)
+	suite.NotContains(strangerIds, hiddenId)
+	suite.Contains(strangerIds, visibleId)
+	suite.Contains(suite.ListIds(repliesPath, suite.FollowerToken), hiddenId)
+
+	hiddenPath := fmt.Sprintf("/replies/hidden?postId=%v", suite.PostID.Hex())
+	suite.Equal([]string{hiddenId}, suite.ListIds(hiddenPath, suite.OwnerToken))
+}
+
+func (suite *CommentFiltersTestSuite) Test_RepliesOfAHiddenCommentAreHiddenFromEveryoneButItsAuthor() {
+	hiddenId := suite.Create(suite.FollowerToken, "spoiler", nil)
+	suite.True(suite.IsHidden(config.CommentsCollection, hiddenId))
+
+	repliesPath := fmt.Sprintf("/replies?replyToId=%v", hiddenId)
+	response, err := suite.ExecuteRequest(http.MethodGet, repliesPath, suite.StrangerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Empty(suite.ListIds(repliesPath, suite.FollowerToken))
+}
+
+func (suite *CommentFiltersTestSuite) Test_CommentsOfThePostOwnerAreNotFiltered() {
+	id := suite.Create(suite.OwnerToken, "No spoiler please", nil)
+	suite.False(suite.IsHidden(config.CommentsCollection, id))
+}
+
+func (suite *CommentFiltersTestSuite) Test_ApprovingAHiddenCommentShowsIt() {
+	hiddenId := suite.Create(suite.FollowerToken, "spoiler", nil)
+
+	response, err := suite.ExecuteRequest(http.MethodPost, "/comments/"+hiddenId+"/unhide", suite.OwnerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ListIds(fmt.Sprintf("/comments?postId=%v", suite.PostID.Hex()), suite.StrangerToken), hiddenId)
+	suite.Empty(suite.ListIds(fmt.Sprintf("/comments/hidden?postId=%v", suite.PostID.Hex()), suite.OwnerToken))
+}
+
+func (suite *CommentFiltersTestSuite) Test_SettingsAreNormalized() {
+	response, err := suite.ExecuteRequest(http.MethodGet, "/users/me/comment-filters", suite.OwnerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	settings, _ := suite.ResponseBody["settings"].(map[string]interface{})
+	suite.Equal([]interface{}{"spoiler"}, settings["keywords"])
+	suite.Equal(false, settings["hideOffensive"])
+}
+
+func TestCommentFiltersTestSuite(t *testing.T) {
+	suite.Run(t, new(CommentFiltersTestSuite))
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteCommentTestSuite struct {
+	suite.Suite
+	CommentID          string
+	CommentsCollection *mongo.Collection
+	OtherCommentID     primitive.ObjectID
+	OwnerToken         string
+	PostID             primitive.ObjectID
+	PostsCollection    *mongo.Collection
+	RepliesCollection  *mongo.Collection
+	ResponseBody       bson.M
+	StrangerToken      string
+	Token              string
+	UsersCollection    *mongo.Collection
+}
+
+func (suite *DeleteCommentTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.CommentsCollection = services.GetMongoDBCollection(config.CommentsCollection)
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.RepliesCollection = services.GetMongoDBCollection(config.RepliesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *DeleteCommentTestSuite) SetupTest() {
+	result, err := mocks.DeleteComment()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentID = result.CommentID.Hex()
+	suite.OtherCommentID = result.OtherCommentID
+	suite.OwnerToken = result.OwnerToken
+	suite.PostID = result.PostID
+	suite.StrangerToken = result.StrangerToken
+	suite.Token = result.Token
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *DeleteCommentTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodDelete, "/comments/"+suite.CommentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *DeleteCommentTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.CommentsCollection, suite.PostsCollection, suite.RepliesCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *DeleteCommentTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	commentId, err := primitive.ObjectIDFromHex(suite.CommentID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.RepliesCollection.CountDocuments(context.Background(), bson.M{"replyToId": commentId, "deletedAt": nil})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	count, err = suite.RepliesCollection.CountDocuments(context.Background(), bson.M{"replyToId": commentId, "deletedWithId": commentId})
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+
+	err = suite.CommentsCollection.FindOne(context.Background(), bson.M{"_id": commentId, "deletedAt": bson.M{"$ne": nil}}).Err()
+	suite.NoError(err)
+
+	count, err = suite.RepliesCollection.CountDocuments(context.Background(), bson.M{"replyToId": suite.OtherCommentID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	post := &models.Post{}
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID}).Decode(post)
+	suite.NoError(err)
+	suite.Equal(1, post.CommentsCount)
+	suite.Equal(1, post.RepliesCount)
+	suite.Len(post.Comments, 1)
+	suite.Equal(suite.OtherCommentID, post.Comments[0].ID)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteCommentTestSuite) Test_SucceedsAndCanBeRestored() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.ResponseBody = bson.M{}
+	request, err := http.NewRequest(http.MethodPost, "/comments/"+suite.CommentID+"/restore", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	recorder := httptest.NewRecorder()
+	routes.SetupRouter().ServeHTTP(recorder, request)
+	err = json.NewDecoder(recorder.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	commentId, err := primitive.ObjectIDFromHex(suite.CommentID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.RepliesCollection.CountDocuments(context.Background(), bson.M{"replyToId": commentId, "deletedAt": nil})
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+
+	post := &models.Post{}
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID}).Decode(post)
+	suite.NoError(err)
+	suite.Equal(2, post.CommentsCount)
+	suite.Equal(3, post.RepliesCount)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteCommentTestSuite) Test_SucceedsIfUserIsPostOwner() {
+	suite.Token = suite.OwnerToken
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteCommentTestSuite) Test_FailsIfUserNotAuthorOrPostOwner() {
+	suite.Token = suite.StrangerToken
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteCommentTestSuite) Test_FailsIfCommentIdIsInvalid() {
+	suite.CommentID = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteCommentTestSuite) Test_FailsIfCommentNotFound() {
+	suite.CommentID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestDeleteCommentTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteCommentTestSuite))
+}
@@ -0,0 +1,286 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ModerateCommentsTestSuite struct {
+	suite.Suite
+	CommentIDs         []primitive.ObjectID
+	CommentsCollection *mongo.Collection
+	FollowerToken      string
+	FollowsCollection  *mongo.Collection
+	OwnerToken         string
+	PostID             primitive.ObjectID
+	PostsCollection    *mongo.Collection
+	ResponseBody       bson.M
+	StrangerToken      string
+	UsersCollection    *mongo.Collection
+}
+
+func (suite *ModerateCommentsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.CommentsCollection = services.GetMongoDBCollection(config.CommentsCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *ModerateCommentsTestSuite) SetupTest() {
+	result, err := mocks.ModerateComments()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentIDs = result.CommentIDs
+	suite.FollowerToken = result.FollowerToken
+	suite.OwnerToken = result.OwnerToken
+	suite.PostID = result.PostID
+	suite.ResponseBody = bson.M{}
+	suite.StrangerToken = result.StrangerToken
+}
+
+func (suite *ModerateCommentsTestSuite) ExecuteRequest(method, path, token string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *ModerateCommentsTestSuite) Moderate(action string, commentId primitive.ObjectID) *httptest.ResponseRecorder {
+	response, err := suite.ExecuteRequest(http.MethodPost, fmt.Sprintf("/comments/%v/%v", commentId.Hex(), action), suite.OwnerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return response
+}
+
+func (suite *ModerateCommentsTestSuite) GetComments(token, query string) []string {
+	response, err := suite.ExecuteRequest(http.MethodGet, fmt.Sprintf("/comments?postId=%v%v", suite.PostID.Hex(), query), token, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	ids := []string{}
+	for _, item := range items {
+		comment, _ := item.(map[string]interface{})
+		ids = append(ids, comment["_id"].(string))
+	}
+
+	return ids
+}
+
+func (suite *ModerateCommentsTestSuite) FindPost() *models.Post {
+	post := &models.Post{}
+	err := suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID}).Decode(post)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return post
+}
+
+func (suite *ModerateCommentsTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{
+		suite.CommentsCollection,
+		suite.FollowsCollection,
+		services.GetMongoDBCollection(config.NotificationsCollection),
+		suite.PostsCollection,
+		suite.UsersCollection,
+	}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *ModerateCommentsTestSuite) Test_OnlyThePostOwnerCanModerate() {
+	for _, action := range []string{"hide", "unhide", "pin", "unpin"} {
+		response, err := suite.ExecuteRequest(http.MethodPost, fmt.Sprintf("/comments/%v/%v", suite.CommentIDs[0].Hex(), action), suite.FollowerToken, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusForbidden, response.Code, action)
+		suite.Contains(suite.ResponseBody, "message")
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPatch, fmt.Sprintf("/posts/%v/comment-settings", suite.PostID.Hex()), suite.FollowerToken, bson.M{"commentsDisabled": true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.False(suite.FindPost().CommentsDisabled)
+}
+
+func (suite *ModerateCommentsTestSuite) Test_HiddenCommentsAreOnlyListedForTheirAuthor() {
+	suite.Equal(http.StatusOK, suite.Moderate("hide", suite.CommentIDs[0]).Code)
+
+	suite.NotContains(suite.GetComments(suite.StrangerToken, ""), suite.CommentIDs[0].Hex())
+	suite.Contains(suite.GetComments(suite.FollowerToken, ""), suite.CommentIDs[0].Hex())
+	for _, comment := range suite.FindPost().Comments {
+		suite.NotEqual(suite.CommentIDs[0], comment.ID)
+	}
+
+	suite.Equal(http.StatusOK, suite.Moderate("unhide", suite.CommentIDs[0]).Code)
+	suite.Contains(suite.GetComments(suite.StrangerToken, ""), suite.CommentIDs[0].Hex())
+}
+
+func (suite *ModerateCommentsTestSuite) Test_HidingAPinnedCommentUnpinsIt() {
+	suite.Equal(http.StatusOK, suite.Moderate("pin", suite.CommentIDs[0]).Code)
+	suite.Equal([]primitive.ObjectID{suite.CommentIDs[0]}, suite.FindPost().PinnedCommentIDs)
+
+	suite.Equal(http.StatusOK, suite.Moderate("hide", suite.CommentIDs[0]).Code)
+	suite.Empty(suite.FindPost().PinnedCommentIDs)
+
+	response := suite.Moderate("pin", suite.CommentIDs[0])
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *ModerateCommentsTestSuite) Test_PinningIsLimited() {
+	for _, commentId := range suite.CommentIDs[:config.MaxPinnedComments] {
+		suite.Equal(http.StatusOK, suite.Moderate("pin", commentId).Code)
+	}
+
+	suite.Equal(http.StatusOK, suite.Moderate("pin", suite.CommentIDs[0]).Code)
+
+	response := suite.Moderate("pin", suite.CommentIDs[config.MaxPinnedComments])
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+	suite.Len(suite.FindPost().PinnedCommentIDs, config.MaxPinnedComments)
+
+	suite.Equal(http.StatusOK, suite.Moderate("unpin", suite.CommentIDs[0]).Code)
+	suite.Equal(http.StatusOK, suite.Moderate("pin", suite.CommentIDs[config.MaxPinnedComments]).Code)
+}
+
+func (suite *ModerateCommentsTestSuite) Test_PinnedCommentsLeadOnlyTheFirstPage() {
+	oldest, secondOldest := suite.CommentIDs[0], suite.CommentIDs[1]
+	suite.Equal(http.StatusOK, suite.Moderate("pin", secondOldest).Code)
+	suite.Equal(http.StatusOK, suite.Moderate("pin", oldest).Code)
+
+	newest := len(suite.CommentIDs) - 1
+	firstPage := suite.GetComments(suite.StrangerToken, "&limit=2")
+	suite.Equal([]string{
+		secondOldest.Hex(),
+		oldest.Hex(),
+		suite.CommentIDs[newest].Hex(),
+		suite.CommentIDs[newest-1].Hex(),
+	}, firstPage)
+
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	pinnedComment, _ := items[0].(map[string]interface{})
+	suite.Equal(true, pinnedComment["isPinned"])
+
+	nextCursor, _ := suite.ResponseBody["nextCursor"].(string)
+	suite.NotEmpty(nextCursor)
+
+	secondPage := suite.GetComments(suite.StrangerToken, "&limit=2&cursor="+nextCursor)
+	suite.Equal([]string{suite.CommentIDs[newest-2].Hex()}, secondPage)
+}
+
+func (suite *ModerateCommentsTestSuite) Test_FollowersCommentsAudience() {
+	path := fmt.Sprintf("/posts/%v/comment-settings", suite.PostID.Hex())
+	response, err := suite.ExecuteRequest(http.MethodPatch, path, suite.OwnerToken, bson.M{"commentsAudience": models.FollowersCommentsAudience})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal(models.FollowersCommentsAudience, suite.ResponseBody["commentsAudience"])
+
+	requestBody := bson.M{"message": "Hello", "postId": suite.PostID.Hex()}
+	response, err = suite.ExecuteRequest(http.MethodPost, "/comments", suite.StrangerToken, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+
+	response, err = suite.ExecuteRequest(http.MethodPost, "/comments", suite.FollowerToken, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusCreated, response.Code)
+}
+
+func (suite *ModerateCommentsTestSuite) Test_DisabledCommentsOnlyAllowTheOwner() {
+	path := fmt.Sprintf("/posts/%v/comment-settings", suite.PostID.Hex())
+	response, err := suite.ExecuteRequest(http.MethodPatch, path, suite.OwnerToken, bson.M{"commentsDisabled": true})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	requestBody := bson.M{"message": "Hello", "postId": suite.PostID.Hex()}
+	response, err = suite.ExecuteRequest(http.MethodPost, "/comments", suite.FollowerToken, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+
+	response, err = suite.ExecuteRequest(http.MethodPost, "/comments", suite.OwnerToken, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusCreated, response.Code)
+}
+
+func (suite *ModerateCommentsTestSuite) Test_FailsIfCommentsAudienceIsInvalid() {
+	path := fmt.Sprintf("/posts/%v/comment-settings", suite.PostID.Hex())
+	response, err := suite.ExecuteRequest(http.MethodPatch, path, suite.OwnerToken, bson.M{"commentsAudience": "friends"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestModerateCommentsTestSuite(t *testing.T) {
+	suite.Run(t, new(ModerateCommentsTestSuite))
+}
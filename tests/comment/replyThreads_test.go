@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReplyThreadsTestSuite struct {
+	suite.Suite
+	Collections    []*mongo.Collection
+	CommentID      primitive.ObjectID
+	FollowerToken  string
+	OtherCommentID primitive.ObjectID
+	OwnerToken     string
+	PostID         primitive.ObjectID
+	ResponseBody   bson.M
+	StrangerToken  string
+}
+
+func (suite *ReplyThreadsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	collectionNames := []string{
+		config.CommentsCollection,
+		config.FollowsCollection,
+		config.NotificationsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collectionNames {
+		suite.Collections = append(suite.Collections, services.GetMongoDBCollection(name))
+	}
+}
+
+func (suite *ReplyThreadsTestSuite) SetupTest() {
+	result, err := mocks.ModerateComments()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentID = result.CommentIDs[0]
+	suite.FollowerToken = result.FollowerToken
+	suite.OtherCommentID = result.CommentIDs[1]
+	suite.OwnerToken = result.OwnerToken
+	suite.PostID = result.PostID
+	suite.ResponseBody = bson.M{}
+	suite.StrangerToken = result.StrangerToken
+}
+
+func (suite *ReplyThreadsTestSuite) ExecuteRequest(method, path, token string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *ReplyThreadsTestSuite) TearDownTest() {
+	for _, collection := range suite.Collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// Reply creates a reply to the comment, or to parentReplyId when it is not empty, and returns the response
+func (suite *ReplyThreadsTestSuite) Reply(token string, commentId primitive.ObjectID, parentReplyId string) *httptest.ResponseRecorder {
+	requestBody := bson.M{"message": "Reply", "postId": suite.PostID.Hex(), "replyToId": commentId.Hex()}
+	if parentReplyId != "" {
+		requestBody["parentReplyId"] = parentReplyId
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPost, "/replies", token, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return response
+}
+
+func (suite *ReplyThreadsTestSuite) ReplyID() string {
+	reply, _ := suite.ResponseBody["reply"].(map[string]interface{})
+	replyId, _ := reply["_id"].(string)
+	return replyId
+}
+
+func (suite *ReplyThreadsTestSuite) Test_RepliesToAReply() {
+	suite.Equal(http.StatusCreated, suite.Reply(suite.OwnerToken, suite.CommentID, "").Code)
+	parentReplyId := suite.ReplyID()
+
+	suite.Equal(http.StatusCreated, suite.Reply(suite.StrangerToken, suite.CommentID, parentReplyId).Code)
+	reply, _ := suite.ResponseBody["reply"].(map[string]interface{})
+	suite.Equal(parentReplyId, reply["parentReplyId"])
+	replyToUser, _ := reply["replyToUser"].(map[string]interface{})
+	suite.Equal("owner", replyToUser["username"])
+
+	response, err := suite.ExecuteRequest(http.MethodGet, fmt.Sprintf("/replies?replyToId=%v", suite.CommentID.Hex()), suite.StrangerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	suite.Len(items, 2)
+
+	replyToUsernames := map[string]interface{}{}
+	for _, item := range items {
+		reply, _ := item.(map[string]interface{})
+		replyToUser, _ := reply["replyToUser"].(map[string]interface{})
+		replyToUsernames[reply["_id"].(string)] = replyToUser["username"]
+	}
+	suite.Equal("follower", replyToUsernames[parentReplyId])
+	suite.Equal("owner", replyToUsernames[reply["_id"].(string)])
+}
+
+func (suite *ReplyThreadsTestSuite) Test_FailsIfParentReplyBelongsToAnotherComment() {
+	suite.Equal(http.StatusCreated, suite.Reply(suite.OwnerToken, suite.OtherCommentID, "").Code)
+	parentReplyId := suite.ReplyID()
+
+	response := suite.Reply(suite.StrangerToken, suite.CommentID, parentReplyId)
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *ReplyThreadsTestSuite) Test_FailsIfCommentIsHiddenFromTheViewer() {
+	_, err := suite.Collections[0].UpdateByID(context.Background(), suite.CommentID, bson.M{"$set": bson.M{"isHidden": true}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, suite.Reply(suite.StrangerToken, suite.CommentID, "").Code)
+	suite.Equal(http.StatusCreated, suite.Reply(suite.FollowerToken, suite.CommentID, "").Code)
+}
+
+func (suite *ReplyThreadsTestSuite) Test_FailsIfParentReplyIsHiddenFromTheViewer() {
+	suite.Equal(http.StatusCreated, suite.Reply(suite.FollowerToken, suite.CommentID, "").Code)
+	parentReplyId := suite.ReplyID()
+
+	id, _ := primitive.ObjectIDFromHex(parentReplyId)
+	_, err := suite.Collections[4].UpdateByID(context.Background(), id, bson.M{"$set": bson.M{"isHidden": true}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, suite.Reply(suite.StrangerToken, suite.CommentID, parentReplyId).Code)
+	suite.Equal(http.StatusCreated, suite.Reply(suite.FollowerToken, suite.CommentID, parentReplyId).Code)
+}
+
+func (suite *ReplyThreadsTestSuite) Test_ThreadReportsMoreReplies() {
+	path := fmt.Sprintf("/comments/%v/thread", suite.CommentID.Hex())
+	for i := 0; i < config.ThreadRepliesLength; i++ {
+		suite.Equal(http.StatusCreated, suite.Reply(suite.StrangerToken, suite.CommentID, "").Code)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodGet, path, suite.StrangerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal(false, suite.ResponseBody["hasMoreReplies"])
+	suite.Len(suite.ResponseBody["replies"], config.ThreadRepliesLength)
+
+	suite.Equal(http.StatusCreated, suite.Reply(suite.StrangerToken, suite.CommentID, "").Code)
+	response, err = suite.ExecuteRequest(http.MethodGet, path, suite.StrangerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal(true, suite.ResponseBody["hasMoreReplies"])
+	suite.Len(suite.ResponseBody["replies"], config.ThreadRepliesLength)
+	replies, _ := suite.ResponseBody["replies"].([]interface{})
+	reply, _ := replies[0].(map[string]interface{})
+	replyToUser, _ := reply["replyToUser"].(map[string]interface{})
+	suite.Equal("follower", replyToUser["username"])
+}
+
+func (suite *ReplyThreadsTestSuite) Test_ThreadListsPriorVersionsOnlyForTheAuthor() {
+	response, err := suite.ExecuteRequest(http.MethodPatch, "/comments/"+suite.CommentID.Hex(), suite.FollowerToken, bson.M{"message": "Edited"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	path := fmt.Sprintf("/comments/%v/thread", suite.CommentID.Hex())
+	response, err = suite.ExecuteRequest(http.MethodGet, path, suite.StrangerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	comment, _ := suite.ResponseBody["comment"].(map[string]interface{})
+	suite.Equal("Edited", comment["message"])
+	suite.NotContains(comment, "edits")
+
+	response, err = suite.ExecuteRequest(http.MethodGet, path, suite.FollowerToken, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	comment, _ = suite.ResponseBody["comment"].(map[string]interface{})
+	suite.Len(comment["edits"], 1)
+}
+
+func TestReplyThreadsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplyThreadsTestSuite))
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UpdateCommentTestSuite struct {
+	suite.Suite
+	Collections   []*mongo.Collection
+	CommentID     primitive.ObjectID
+	OwnerToken    string
+	PostID        primitive.ObjectID
+	ResponseBody  bson.M
+	StrangerToken string
+	Token         string
+}
+
+func (suite *UpdateCommentTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	collectionNames := []string{
+		config.CommentsCollection,
+		config.NotificationsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collectionNames {
+		suite.Collections = append(suite.Collections, services.GetMongoDBCollection(name))
+	}
+}
+
+func (suite *UpdateCommentTestSuite) SetupTest() {
+	result, err := mocks.UpdateComment()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentID = result.CommentID
+	suite.OwnerToken = result.OwnerToken
+	suite.PostID = result.PostID
+	suite.ResponseBody = bson.M{}
+	suite.StrangerToken = result.StrangerToken
+	suite.Token = result.Token
+}
+
+func (suite *UpdateCommentTestSuite) ExecuteRequest(method, path, token string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *UpdateCommentTestSuite) TearDownTest() {
+	for _, collection := range suite.Collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *UpdateCommentTestSuite) Edit(path, token, message string) *httptest.ResponseRecorder {
+	response, err := suite.ExecuteRequest(http.MethodPatch, path, token, bson.M{"message": message})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return response
+}
+
+// FindItem lists path as the viewer and returns the item with the id
+func (suite *UpdateCommentTestSuite) FindItem(path, token, id string) map[string]interface{} {
+	response, err := suite.ExecuteRequest(http.MethodGet, path, token, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	for _, item := range items {
+		document, _ := item.(map[string]interface{})
+		if document["_id"] == id {
+			return document
+		}
+	}
+
+	suite.Failf("item not found", "%v is not listed in %v", id, path)
+	return nil
+}
+
+func editMessages(document map[string]interface{}) []string {
+	edits, _ := document["edits"].([]interface{})
+	messages := []string{}
+	for _, edit := range edits {
+		version, _ := edit.(map[string]interface{})
+		messages = append(messages, version["message"].(string))
+	}
+
+	return messages
+}
+
+func (suite *UpdateCommentTestSuite) Test_KeepsPriorVersions() {
+	path := "/comments/" + suite.CommentID.Hex()
+	suite.Equal(http.StatusOK, suite.Edit(path, suite.Token, "First edit").Code)
+	suite.Equal(http.StatusOK, suite.Edit(path, suite.Token, "Second edit").Code)
+
+	comment, _ := suite.ResponseBody["comment"].(map[string]interface{})
+	suite.Equal("Second edit", comment["message"])
+	suite.NotEmpty(comment["editedAt"])
+	suite.Equal([]string{"Comment", "First edit"}, editMessages(comment))
+}
+
+func (suite *UpdateCommentTestSuite) Test_SameMessageIsNotAnEdit() {
+	suite.Equal(http.StatusOK, suite.Edit("/comments/"+suite.CommentID.Hex(), suite.Token, "Comment").Code)
+
+	comment, _ := suite.ResponseBody["comment"].(map[string]interface{})
+	suite.NotContains(comment, "editedAt")
+	suite.NotContains(comment, "edits")
+}
+
+func (suite *UpdateCommentTestSuite) Test_PriorVersionsAreOnlyListedForTheAuthor() {
+	suite.Equal(http.StatusOK, suite.Edit("/comments/"+suite.CommentID.Hex(), suite.Token, "Edited").Code)
+
+	commentsPath := fmt.Sprintf("/comments?postId=%v", suite.PostID.Hex())
+	comment := suite.FindItem(commentsPath, suite.StrangerToken, suite.CommentID.Hex())
+	suite.Equal("Edited", comment["message"])
+	suite.Contains(comment, "editedAt")
+	suite.NotContains(comment, "edits")
+
+	comment = suite.FindItem(commentsPath, suite.Token, suite.CommentID.Hex())
+	suite.Equal([]string{"Comment"}, editMessages(comment))
+}
+
+func (suite *UpdateCommentTestSuite) Test_RepliesKeepPriorVersionsForTheirAuthor() {
+	requestBody := bson.M{"message": "Reply", "postId": suite.PostID.Hex(), "replyToId": suite.CommentID.Hex()}
+	response, err := suite.ExecuteRequest(http.MethodPost, "/replies", suite.Token, requestBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusCreated, response.Code)
+	reply, _ := suite.ResponseBody["reply"].(map[string]interface{})
+	replyId, _ := reply["_id"].(string)
+
+	suite.Equal(http.StatusOK, suite.Edit("/replies/"+replyId, suite.Token, "Edited reply").Code)
+	reply, _ = suite.ResponseBody["reply"].(map[string]interface{})
+	suite.Equal([]string{"Reply"}, editMessages(reply))
+
+	repliesPath := fmt.Sprintf("/replies?replyToId=%v", suite.CommentID.Hex())
+	reply = suite.FindItem(repliesPath, suite.StrangerToken, replyId)
+	suite.Equal("Edited reply", reply["message"])
+	suite.NotContains(reply, "edits")
+
+	reply = suite.FindItem(repliesPath, suite.Token, replyId)
+	suite.Equal([]string{"Reply"}, editMessages(reply))
+}
+
+func (suite *UpdateCommentTestSuite) Test_FailsOutsideTheEditWindow() {
+	createdAt := time.Now().Add(-config.CommentEditWindow - time.Minute)
+	_, err := suite.Collections[0].UpdateByID(context.Background(), suite.CommentID, bson.M{"$set": bson.M{"createdAt": createdAt}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response := suite.Edit("/comments/"+suite.CommentID.Hex(), suite.Token, "Too late")
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *UpdateCommentTestSuite) Test_FailsIfViewerIsNotTheAuthor() {
+	response := suite.Edit("/comments/"+suite.CommentID.Hex(), suite.OwnerToken, "Not mine")
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *UpdateCommentTestSuite) Test_FailsIfMessageIsMissing() {
+	response := suite.Edit("/comments/"+suite.CommentID.Hex(), suite.Token, "")
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestUpdateCommentTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateCommentTestSuite))
+}
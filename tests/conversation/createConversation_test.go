@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type CreateConversationTestSuite struct {
+	suite.Suite
+	ConversationsCollection *mongo.Collection
+	FollowerID              primitive.ObjectID
+	FollowsCollection       *mongo.Collection
+	RequestBody             bson.M
+	ResponseBody            bson.M
+	StrangerID              primitive.ObjectID
+	Token                   string
+	UserID                  primitive.ObjectID
+	UsersCollection         *mongo.Collection
+}
+
+func (suite *CreateConversationTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.ConversationsCollection = services.GetMongoDBCollection(config.ConversationsCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *CreateConversationTestSuite) SetupTest() {
+	result, err := mocks.CreateConversation()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.FollowerID = result.FollowerID
+	suite.StrangerID = result.StrangerID
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+	suite.ResponseBody = bson.M{}
+	suite.RequestBody = bson.M{"userIds": bson.A{suite.FollowerID.Hex()}}
+}
+
+func (suite *CreateConversationTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(suite.RequestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "/conversations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *CreateConversationTestSuite) TearDownTest() {
+	for _, collection := range []*mongo.Collection{suite.ConversationsCollection, suite.FollowsCollection, suite.UsersCollection} {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *CreateConversationTestSuite) findMemberStatus(userId primitive.ObjectID) string {
+	conversation := &models.Conversation{}
+	err := suite.ConversationsCollection.FindOne(context.Background(), bson.M{"memberIds": userId}).Decode(conversation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return conversation.FindMember(userId).Status
+}
+
+func (suite *CreateConversationTestSuite) Test_SucceedsWithFollower() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusCreated)
+	suite.Contains(suite.ResponseBody, "conversation")
+	suite.Equal(suite.findMemberStatus(suite.FollowerID), models.AcceptedConversationStatus)
+}
+
+func (suite *CreateConversationTestSuite) Test_SucceedsAsMessageRequest() {
+	suite.RequestBody = bson.M{"userIds": bson.A{suite.StrangerID.Hex()}}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusCreated)
+	suite.Equal(suite.findMemberStatus(suite.StrangerID), models.PendingConversationStatus)
+}
+
+func (suite *CreateConversationTestSuite) Test_SucceedsWithExistingConversation() {
+	_, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.ConversationsCollection.CountDocuments(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Equal(count, int64(1))
+}
+
+func (suite *CreateConversationTestSuite) Test_FailsWithOnlyYourself() {
+	suite.RequestBody = bson.M{"userIds": bson.A{suite.UserID.Hex()}}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusBadRequest)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *CreateConversationTestSuite) Test_FailsIfUserNotFound() {
+	suite.RequestBody = bson.M{"userIds": bson.A{primitive.NewObjectID().Hex()}}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestCreateConversationTestSuite(t *testing.T) {
+	suite.Run(t, new(CreateConversationTestSuite))
+}
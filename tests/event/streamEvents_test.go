@@ -0,0 +1,272 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The change streams are opened in the background, so events written right after connecting could be missed
+const streamStartWait = time.Second
+
+type StreamEventsTestSuite struct {
+	suite.Suite
+	Mock   *mocks.EventRouteMockResult
+	Server *httptest.Server
+}
+
+func (suite *StreamEventsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.Server = httptest.NewServer(routes.SetupRouter())
+}
+
+func (suite *StreamEventsTestSuite) TearDownSuite() {
+	suite.Server.Close()
+}
+
+func (suite *StreamEventsTestSuite) SetupTest() {
+	result, err := mocks.StreamEvents()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Mock = result
+}
+
+func (suite *StreamEventsTestSuite) TearDownTest() {
+	collections := []string{config.BlocksCollection, config.CommentsCollection, config.PostsCollection, config.UsersCollection}
+	for _, name := range collections {
+		_, err := services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *StreamEventsTestSuite) DialWebSocket() *websocket.Conn {
+	header := http.Header{}
+	header.Set("Cookie", (&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Mock.Token}).String())
+	url := "ws" + strings.TrimPrefix(suite.Server.URL, "http") + "/events"
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	return conn
+}
+
+func (suite *StreamEventsTestSuite) InsertComment(postId, userId primitive.ObjectID) {
+	comment := models.Comment{ID: primitive.NewObjectID(), CreatedAt: time.Now(), Message: "Comment", PostID: postId, UserID: userId}
+	_, err := services.GetMongoDBCollection(config.CommentsCollection).InsertOne(context.Background(), comment)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readWebSocketEvent(conn *websocket.Conn) bson.M {
+	event := bson.M{}
+	err := conn.ReadJSON(&event)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return event
+}
+
+// readSSEvent skips comments and returns the name and data of the next event
+func readSSEvent(reader *bufio.Reader) (string, bson.M) {
+	name := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "event:") {
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		}
+
+		if strings.HasPrefix(line, "data:") {
+			data := bson.M{}
+			err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &data)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			return name, data
+		}
+	}
+}
+
+func (suite *StreamEventsTestSuite) Test_WebSocketSendsUnreadCountFirst() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+
+	event := readWebSocketEvent(conn)
+	suite.Equal(services.NotificationsCountEvent, event["event"])
+	suite.Equal(map[string]interface{}{"unreadCount": float64(0)}, event["data"])
+}
+
+func (suite *StreamEventsTestSuite) Test_WebSocketStreamsCommentsOfSubscribedPosts() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+	readWebSocketEvent(conn)
+
+	err := conn.WriteJSON(bson.M{"action": "subscribe", "postId": suite.Mock.PostID.Hex()})
+	suite.NoError(err)
+	time.Sleep(streamStartWait)
+
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.CommenterID)
+
+	event := readWebSocketEvent(conn)
+	suite.Equal(services.CommentEvent, event["event"])
+	suite.Equal(suite.Mock.CommenterID.Hex(), event["data"].(map[string]interface{})["userId"])
+}
+
+// A comment of the blocked user is written first, so it would be the first event if it was not filtered out
+func (suite *StreamEventsTestSuite) Test_WebSocketSkipsEventsOfBlockedUsers() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+	readWebSocketEvent(conn)
+
+	err := conn.WriteJSON(bson.M{"action": "subscribe", "postId": suite.Mock.PostID.Hex()})
+	suite.NoError(err)
+	time.Sleep(streamStartWait)
+
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.BlockedID)
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.CommenterID)
+
+	event := readWebSocketEvent(conn)
+	suite.Equal(services.CommentEvent, event["event"])
+	suite.Equal(suite.Mock.CommenterID.Hex(), event["data"].(map[string]interface{})["userId"])
+}
+
+// The commenter is blocked after the stream was opened
+func (suite *StreamEventsTestSuite) Test_WebSocketSkipsEventsOfUsersBlockedWhileStreaming() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+	readWebSocketEvent(conn)
+
+	err := conn.WriteJSON(bson.M{"action": "subscribe", "postId": suite.Mock.PostID.Hex()})
+	suite.NoError(err)
+	time.Sleep(streamStartWait)
+
+	block := models.Block{ID: primitive.NewObjectID(), BlockerID: suite.Mock.UserID, UserID: suite.Mock.CommenterID}
+	_, err = services.GetMongoDBCollection(config.BlocksCollection).InsertOne(context.Background(), block)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.CommenterID)
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.UserID)
+
+	event := readWebSocketEvent(conn)
+	suite.Equal(services.CommentEvent, event["event"])
+	suite.Equal(suite.Mock.UserID.Hex(), event["data"].(map[string]interface{})["userId"])
+}
+
+func (suite *StreamEventsTestSuite) Test_WebSocketFailsToSubscribeToPostOfBlockedUser() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+	readWebSocketEvent(conn)
+
+	err := conn.WriteJSON(bson.M{"action": "subscribe", "postId": suite.Mock.BlockedPostID.Hex()})
+	suite.NoError(err)
+
+	event := readWebSocketEvent(conn)
+	suite.Equal("error", event["event"])
+	suite.Equal("User not found", event["data"].(map[string]interface{})["message"])
+}
+
+func (suite *StreamEventsTestSuite) Test_WebSocketFailsIfActionIsNotSupported() {
+	conn := suite.DialWebSocket()
+	defer conn.Close()
+	readWebSocketEvent(conn)
+
+	err := conn.WriteJSON(bson.M{"action": "delete", "postId": suite.Mock.PostID.Hex()})
+	suite.NoError(err)
+
+	event := readWebSocketEvent(conn)
+	suite.Equal("error", event["event"])
+}
+
+func (suite *StreamEventsTestSuite) Test_SSEStreamsCommentsOfSubscribedPosts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, suite.Server.URL+"/events?postId="+suite.Mock.PostID.Hex(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Mock.Token})
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Contains(response.Header.Get("Content-Type"), "text/event-stream")
+
+	reader := bufio.NewReader(response.Body)
+	name, data := readSSEvent(reader)
+	suite.Equal(services.NotificationsCountEvent, name)
+	suite.Equal(float64(0), data["unreadCount"])
+
+	time.Sleep(streamStartWait)
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.BlockedID)
+	suite.InsertComment(suite.Mock.PostID, suite.Mock.CommenterID)
+
+	name, data = readSSEvent(reader)
+	suite.Equal(services.CommentEvent, name)
+	suite.Equal(suite.Mock.CommenterID.Hex(), data["userId"])
+}
+
+func (suite *StreamEventsTestSuite) Test_SSEFailsIfPostIdIsInvalid() {
+	request, err := http.NewRequest(http.MethodGet, "/events?postId=invalid", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Mock.Token})
+	response := httptest.NewRecorder()
+	routes.SetupRouter().ServeHTTP(response, request)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (suite *StreamEventsTestSuite) Test_FailsIfUserNotLoggedIn() {
+	request, err := http.NewRequest(http.MethodGet, "/events", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response := httptest.NewRecorder()
+	routes.SetupRouter().ServeHTTP(response, request)
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestStreamEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamEventsTestSuite))
+}
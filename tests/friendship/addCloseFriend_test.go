@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AddCloseFriendTestSuite struct {
+	suite.Suite
+	AuthUserID             primitive.ObjectID
+	BlocksCollection       *mongo.Collection
+	CloseFriendsCollection *mongo.Collection
+	OtherID                primitive.ObjectID
+	ResponseBody           bson.M
+	Token                  string
+	UserID                 string
+	UsersCollection        *mongo.Collection
+}
+
+func (suite *AddCloseFriendTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.BlocksCollection = services.GetMongoDBCollection(config.BlocksCollection)
+	suite.CloseFriendsCollection = services.GetMongoDBCollection(config.CloseFriendsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *AddCloseFriendTestSuite) SetupTest() {
+	result, err := mocks.AddCloseFriend()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.AuthUserID = result.FollowerID
+	suite.OtherID = result.OtherID
+	suite.Token = result.Token
+	suite.UserID = result.UserID.Hex()
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *AddCloseFriendTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/friendships/"+suite.UserID+"/add-close-friend", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *AddCloseFriendTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.BlocksCollection, suite.CloseFriendsCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *AddCloseFriendTestSuite) Test_Succeeds() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	userId, err := primitive.ObjectIDFromHex(suite.UserID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.CloseFriendsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.AuthUserID, "friendId": userId})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *AddCloseFriendTestSuite) Test_FailsIfUserIsSelf() {
+	suite.UserID = suite.AuthUserID.Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *AddCloseFriendTestSuite) Test_FailsIfUserIdIsInvalid() {
+	suite.UserID = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *AddCloseFriendTestSuite) Test_FailsIfUserNotFound() {
+	suite.UserID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *AddCloseFriendTestSuite) Test_FailsIfUserHasBlockedViewer() {
+	suite.UserID = suite.OtherID.Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestAddCloseFriendTestSuite(t *testing.T) {
+	suite.Run(t, new(AddCloseFriendTestSuite))
+}
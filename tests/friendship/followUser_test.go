@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type FollowUserTestSuite struct {
+	suite.Suite
+	FollowerID              primitive.ObjectID
+	FollowsCollection       *mongo.Collection
+	NotificationsCollection *mongo.Collection
+	ResponseBody            bson.M
+	Token                   string
+	TimelinesCollection     *mongo.Collection
+	UserID                  string
+	UsersCollection         *mongo.Collection
+}
+
+func (suite *FollowUserTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.NotificationsCollection = services.GetMongoDBCollection(config.NotificationsCollection)
+	suite.TimelinesCollection = services.GetMongoDBCollection(config.TimelinesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *FollowUserTestSuite) SetupTest() {
+	result, err := mocks.FollowUser()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.FollowerID = result.FollowerID
+	suite.Token = result.Token
+	suite.UserID = result.UserID.Hex()
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *FollowUserTestSuite) ExecuteRequest(action string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/friendships/"+suite.UserID+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *FollowUserTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.FollowsCollection, suite.NotificationsCollection, suite.TimelinesCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *FollowUserTestSuite) FindUsers() (*models.User, *models.User) {
+	userId, err := primitive.ObjectIDFromHex(suite.UserID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	user := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": userId}).Decode(user)
+	suite.NoError(err)
+
+	follower := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.FollowerID}).Decode(follower)
+	suite.NoError(err)
+
+	return user, follower
+}
+
+func (suite *FollowUserTestSuite) Test_FollowIsIdempotent() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest("follow")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	count, err := suite.FollowsCollection.CountDocuments(context.Background(), bson.M{"followerId": suite.FollowerID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	user, follower := suite.FindUsers()
+	suite.Equal(2, user.FollowersCount)
+	suite.Equal(1, follower.FollowingCount)
+}
+
+func (suite *FollowUserTestSuite) Test_UnfollowIsIdempotent() {
+	response, err := suite.ExecuteRequest("follow")
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.Equal(http.StatusOK, response.Code)
+
+	for i := 0; i < 2; i++ {
+		response, err = suite.ExecuteRequest("unfollow")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	count, err := suite.FollowsCollection.CountDocuments(context.Background(), bson.M{"followerId": suite.FollowerID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	user, follower := suite.FindUsers()
+	suite.Equal(1, user.FollowersCount)
+	suite.Equal(0, follower.FollowingCount)
+}
+
+func (suite *FollowUserTestSuite) Test_RefollowingDoesNotNotifyAgain() {
+	for _, action := range []string{"follow", "unfollow", "follow"} {
+		response, err := suite.ExecuteRequest(action)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+
+		_, err = suite.NotificationsCollection.UpdateMany(context.Background(), bson.M{}, bson.M{"$set": bson.M{"read": true}})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	count, err := suite.NotificationsCollection.CountDocuments(context.Background(), bson.M{"type": models.FollowNotification, "actorIds": suite.FollowerID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *FollowUserTestSuite) Test_FailsIfUserIdIsInvalid() {
+	suite.UserID = "invalid"
+
+	response, err := suite.ExecuteRequest("follow")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestFollowUserTestSuite(t *testing.T) {
+	suite.Run(t, new(FollowUserTestSuite))
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetMutualFollowersTestSuite struct {
+	suite.Suite
+	FollowsCollection *mongo.Collection
+	ResponseBody      bson.M
+	Token             string
+	UserID            string
+	UsersCollection   *mongo.Collection
+}
+
+func (suite *GetMutualFollowersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetMutualFollowersTestSuite) SetupTest() {
+	result, err := mocks.GetMutualFollowers()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = result.Token
+	suite.UserID = result.UserID.Hex()
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *GetMutualFollowersTestSuite) ExecuteRequest(path string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetMutualFollowersTestSuite) TearDownTest() {
+	for _, collection := range []*mongo.Collection{suite.FollowsCollection, suite.UsersCollection} {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetMutualFollowersTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest("/friendships/" + suite.UserID + "/mutual?limit=1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+	suite.Len(items, 1)
+	suite.Equal("mutual2", items[0].(map[string]interface{})["username"])
+	suite.Equal(true, suite.ResponseBody["hasNextPage"])
+
+	cursor, ok := suite.ResponseBody["nextCursor"].(string)
+	suite.True(ok)
+
+	suite.ResponseBody = bson.M{}
+	response, err = suite.ExecuteRequest("/friendships/" + suite.UserID + "/mutual?limit=1&cursor=" + cursor)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, ok = suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+	suite.Len(items, 1)
+	suite.Equal("mutual1", items[0].(map[string]interface{})["username"])
+	suite.Equal(false, suite.ResponseBody["hasNextPage"])
+}
+
+func (suite *GetMutualFollowersTestSuite) Test_ProfileIncludesFollowedByAndRelationship() {
+	response, err := suite.ExecuteRequest("/users/user")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	followedBy, ok := suite.ResponseBody["followedBy"].(map[string]interface{})
+	suite.True(ok)
+	suite.Equal(float64(2), followedBy["count"])
+	suite.Len(followedBy["users"], 2)
+
+	relationship, ok := suite.ResponseBody["relationship"].(map[string]interface{})
+	suite.True(ok)
+	suite.Equal(true, relationship["following"])
+	suite.Equal(false, relationship["followedBy"])
+	suite.Equal(false, relationship["requested"])
+	suite.Equal(false, relationship["blocked"])
+}
+
+func (suite *GetMutualFollowersTestSuite) Test_FailsIfUserNotFound() {
+	response, err := suite.ExecuteRequest("/friendships/" + primitive.NewObjectID().Hex() + "/mutual")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetMutualFollowersTestSuite(t *testing.T) {
+	suite.Run(t, new(GetMutualFollowersTestSuite))
+}
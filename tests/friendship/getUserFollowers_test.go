@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetUserFollowersTestSuite struct {
+	suite.Suite
+	FollowsCollection *mongo.Collection
+	OtherID           primitive.ObjectID
+	ResponseBody      bson.M
+	UserID            string
+	UsersCollection   *mongo.Collection
+}
+
+func (suite *GetUserFollowersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetUserFollowersTestSuite) SetupTest() {
+	result, err := mocks.FollowUser()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.OtherID = result.OtherID
+	suite.UserID = result.UserID.Hex()
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *GetUserFollowersTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, "/friendships/"+suite.UserID+"/followers", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetUserFollowersTestSuite) TearDownTest() {
+	for _, collection := range []*mongo.Collection{suite.FollowsCollection, suite.UsersCollection} {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetUserFollowersTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+	suite.Len(items, 1)
+	suite.Equal(suite.OtherID.Hex(), items[0].(map[string]interface{})["_id"])
+	suite.Equal(false, suite.ResponseBody["hasNextPage"])
+}
+
+func (suite *GetUserFollowersTestSuite) Test_FailsIfUserNotFound() {
+	suite.UserID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetUserFollowersTestSuite(t *testing.T) {
+	suite.Run(t, new(GetUserFollowersTestSuite))
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RemoveFollowerTestSuite struct {
+	suite.Suite
+	FollowerID          string
+	FollowsCollection   *mongo.Collection
+	ResponseBody        bson.M
+	TimelinesCollection *mongo.Collection
+	Token               string
+	UserID              primitive.ObjectID
+	UsersCollection     *mongo.Collection
+}
+
+func (suite *RemoveFollowerTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.TimelinesCollection = services.GetMongoDBCollection(config.TimelinesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *RemoveFollowerTestSuite) SetupTest() {
+	result, err := mocks.RemoveFollower()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.FollowerID = result.FollowerID.Hex()
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *RemoveFollowerTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/friendships/"+suite.FollowerID+"/remove-follower", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *RemoveFollowerTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.FollowsCollection, suite.TimelinesCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *RemoveFollowerTestSuite) Test_Succeeds() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	followerId, err := primitive.ObjectIDFromHex(suite.FollowerID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.FollowsCollection.CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	count, err = suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"userId": followerId})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	user := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID}).Decode(user)
+	suite.NoError(err)
+	suite.Equal(0, user.FollowersCount)
+
+	follower := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": followerId}).Decode(follower)
+	suite.NoError(err)
+	suite.Equal(0, follower.FollowingCount)
+}
+
+func (suite *RemoveFollowerTestSuite) Test_FailsIfFollowerIsUser() {
+	suite.FollowerID = suite.UserID.Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *RemoveFollowerTestSuite) Test_FailsIfUserNotFound() {
+	suite.FollowerID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestRemoveFollowerTestSuite(t *testing.T) {
+	suite.Run(t, new(RemoveFollowerTestSuite))
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BackfillTimelinesTestSuite struct {
+	suite.Suite
+	Mock                *mocks.TimelineJobMockResult
+	TimelinesCollection *mongo.Collection
+	UsersCollection     *mongo.Collection
+}
+
+func (suite *BackfillTimelinesTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.TimelinesCollection = services.GetMongoDBCollection(config.TimelinesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *BackfillTimelinesTestSuite) SetupTest() {
+	result, err := mocks.BackfillTimelines()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Mock = result
+}
+
+func (suite *BackfillTimelinesTestSuite) TearDownTest() {
+	collections := []string{config.FollowsCollection, config.PostsCollection, config.TimelinesCollection, config.UsersCollection}
+	for _, name := range collections {
+		_, err := services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *BackfillTimelinesTestSuite) Test_Succeeds() {
+	report, err := jobs.BackfillTimelines(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(4), report.Users)
+	suite.Equal(int64(2), report.Follows)
+	suite.Equal(int64(1), report.Unflagged)
+
+	count, err := suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"userId": suite.Mock.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(4), count)
+
+	count, err = suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"authorId": suite.Mock.FanOutOnReadID, "userId": suite.Mock.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	dropped := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.Mock.DroppedID}).Decode(dropped)
+	suite.NoError(err)
+	suite.False(models.IsFanOutOnRead(dropped))
+}
+
+func (suite *BackfillTimelinesTestSuite) Test_CanBeRunAgain() {
+	_, err := jobs.BackfillTimelines(context.Background())
+	suite.NoError(err)
+
+	report, err := jobs.BackfillTimelines(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(0), report.Unflagged)
+
+	count, err := suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"userId": suite.Mock.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(4), count)
+}
+
+func TestBackfillTimelinesTestSuite(t *testing.T) {
+	suite.Run(t, new(BackfillTimelinesTestSuite))
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteAccountsTestSuite struct {
+	suite.Suite
+	Mock *mocks.AccountMockResult
+}
+
+func (suite *DeleteAccountsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+}
+
+func (suite *DeleteAccountsTestSuite) SetupTest() {
+	result, err := mocks.DeleteAccount()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Mock = result
+	update := bson.M{"$set": bson.M{"deactivatedAt": time.Now(), "deletionRequestedAt": time.Now()}}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err = usersCollection.UpdateByID(context.Background(), suite.Mock.UserID, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *DeleteAccountsTestSuite) TearDownTest() {
+	collections := []string{
+		config.CommentsCollection,
+		config.FollowsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.SavedCollectionPostsCollection,
+		config.SavedCollectionsCollection,
+		config.UserDetailsCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collections {
+		_, err := services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *DeleteAccountsTestSuite) Test_Succeeds() {
+	report, err := jobs.DeleteRequestedAccounts(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(1), report.Accounts)
+	suite.Equal(int64(1), report.Posts)
+	suite.Equal(int64(1), report.Comments)
+
+	err = services.GetMongoDBCollection(config.UsersCollection).FindOne(context.Background(), bson.M{"_id": suite.Mock.UserID}).Err()
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	err = services.GetMongoDBCollection(config.PostsCollection).FindOne(context.Background(), bson.M{"_id": suite.Mock.PostID}).Err()
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	count, err := services.GetMongoDBCollection(config.FollowsCollection).CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	count, err = services.GetMongoDBCollection(config.RepliesCollection).CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	friend := &models.User{}
+	err = services.GetMongoDBCollection(config.UsersCollection).FindOne(context.Background(), bson.M{"_id": suite.Mock.FriendID}).Decode(friend)
+	suite.NoError(err)
+	suite.Equal(0, friend.FollowersCount)
+	suite.Equal(0, friend.FollowingCount)
+
+	post := &models.Post{}
+	err = services.GetMongoDBCollection(config.PostsCollection).FindOne(context.Background(), bson.M{"_id": suite.Mock.FriendPostID}).Decode(post)
+	suite.NoError(err)
+	suite.Equal(0, post.CommentsCount)
+	suite.Equal(0, post.RepliesCount)
+
+	userDetails := &models.UserDetails{}
+	err = services.GetMongoDBCollection(config.UserDetailsCollection).FindOne(context.Background(), bson.M{"userId": suite.Mock.FriendID}).Decode(userDetails)
+	suite.NoError(err)
+	suite.Equal(bson.A{suite.Mock.FriendPostID}, userDetails.SavedPosts)
+	suite.Equal(1, userDetails.SavedPostsCount)
+
+	collection := &models.SavedCollection{}
+	err = services.GetMongoDBCollection(config.SavedCollectionsCollection).FindOne(context.Background(), bson.M{"_id": suite.Mock.CollectionID}).Decode(collection)
+	suite.NoError(err)
+	suite.Equal(0, collection.PostsCount)
+}
+
+func (suite *DeleteAccountsTestSuite) Test_CanBeRunAgain() {
+	_, err := jobs.DeleteRequestedAccounts(context.Background())
+	suite.NoError(err)
+
+	report, err := jobs.DeleteRequestedAccounts(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(0), report.Accounts)
+}
+
+func (suite *DeleteAccountsTestSuite) Test_LeavesAccountsWithoutDeletionRequest() {
+	update := bson.M{"$unset": bson.M{"deletionRequestedAt": ""}}
+	usersCollection := services.GetMongoDBCollection(config.UsersCollection)
+	_, err := usersCollection.UpdateByID(context.Background(), suite.Mock.UserID, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := jobs.DeleteRequestedAccounts(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(0), report.Accounts)
+
+	err = usersCollection.FindOne(context.Background(), bson.M{"_id": suite.Mock.UserID}).Err()
+	suite.NoError(err)
+}
+
+func TestDeleteAccountsTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteAccountsTestSuite))
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MigrateFollowsTestSuite struct {
+	suite.Suite
+	FollowerID            primitive.ObjectID
+	FollowsCollection     *mongo.Collection
+	OtherID               primitive.ObjectID
+	UserDetailsCollection *mongo.Collection
+	UserID                primitive.ObjectID
+}
+
+func (suite *MigrateFollowsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UserDetailsCollection = services.GetMongoDBCollection(config.UserDetailsCollection)
+}
+
+func (suite *MigrateFollowsTestSuite) SetupTest() {
+	result, err := mocks.MigrateFollows()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.FollowerID = result.FollowerID
+	suite.OtherID = result.OtherID
+	suite.UserID = result.UserID
+}
+
+func (suite *MigrateFollowsTestSuite) TearDownTest() {
+	for _, collection := range []*mongo.Collection{suite.FollowsCollection, suite.UserDetailsCollection} {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *MigrateFollowsTestSuite) Test_Succeeds() {
+	report, err := jobs.MigrateFollows(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(3), report.Buckets)
+	suite.Equal(int64(2), report.Follows)
+
+	followerIds, err := models.FindFollowerIds(context.Background(), suite.UserID)
+	suite.NoError(err)
+	suite.Equal(bson.A{suite.OtherID, suite.FollowerID}, followerIds)
+
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	count, err = suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "followers": bson.M{"$exists": false}, "savedPostsCount": 1})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *MigrateFollowsTestSuite) Test_CanBeRunAgain() {
+	_, err := jobs.MigrateFollows(context.Background())
+	suite.NoError(err)
+
+	report, err := jobs.MigrateFollows(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(0), report.Follows)
+
+	count, err := suite.FollowsCollection.CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+}
+
+func TestMigrateFollowsTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrateFollowsTestSuite))
+}
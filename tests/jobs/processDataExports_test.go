@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ProcessDataExportsTestSuite struct {
+	suite.Suite
+	DataExportsCollection *mongo.Collection
+}
+
+func (suite *ProcessDataExportsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.DataExportsCollection = services.GetMongoDBCollection(config.DataExportsCollection)
+}
+
+func (suite *ProcessDataExportsTestSuite) TearDownTest() {
+	_, err := suite.DataExportsCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *ProcessDataExportsTestSuite) InsertExport(status string, createdAt time.Time, fields bson.M) primitive.ObjectID {
+	export := &models.DataExport{}
+	export.NormalizeFields(primitive.NewObjectID())
+	export.CreatedAt = createdAt
+	export.Status = status
+	_, err := suite.DataExportsCollection.InsertOne(context.Background(), export)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(fields) > 0 {
+		_, err = suite.DataExportsCollection.UpdateByID(context.Background(), export.ID, bson.M{"$set": fields})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return export.ID
+}
+
+func (suite *ProcessDataExportsTestSuite) Test_ClaimsEachPendingExportOnce() {
+	newerId := suite.InsertExport(models.PendingDataExportStatus, time.Now(), nil)
+	olderId := suite.InsertExport(models.PendingDataExportStatus, time.Now().Add(-time.Minute), nil)
+
+	export, err := models.ClaimDataExport(context.Background())
+	suite.NoError(err)
+	suite.Equal(olderId, export.ID)
+	suite.Equal(models.ProcessingDataExportStatus, export.Status)
+
+	export, err = models.ClaimDataExport(context.Background())
+	suite.NoError(err)
+	suite.Equal(newerId, export.ID)
+
+	export, err = models.ClaimDataExport(context.Background())
+	suite.NoError(err)
+	suite.Nil(export)
+}
+
+func (suite *ProcessDataExportsTestSuite) Test_ReclaimsExportsLeftProcessing() {
+	staleId := suite.InsertExport(models.ProcessingDataExportStatus, time.Now(), bson.M{"claimedAt": time.Now().Add(-2 * config.DataExportClaimTimeout)})
+	suite.InsertExport(models.ProcessingDataExportStatus, time.Now(), bson.M{"claimedAt": time.Now()})
+
+	export, err := models.ClaimDataExport(context.Background())
+	suite.NoError(err)
+	suite.Equal(staleId, export.ID)
+
+	export, err = models.ClaimDataExport(context.Background())
+	suite.NoError(err)
+	suite.Nil(export)
+}
+
+func (suite *ProcessDataExportsTestSuite) Test_ExpiresExportsAfterTheirLinks() {
+	expiredId := suite.InsertExport(models.CompletedDataExportStatus, time.Now(), bson.M{"completedAt": time.Now().Add(-2 * config.DataExportLinkTTL)})
+	recentId := suite.InsertExport(models.CompletedDataExportStatus, time.Now(), bson.M{"completedAt": time.Now()})
+
+	report, err := jobs.ProcessDataExports(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(1), report.Expired)
+	suite.Equal(int64(0), report.Completed)
+
+	export := &models.DataExport{}
+	err = suite.DataExportsCollection.FindOne(context.Background(), bson.M{"_id": expiredId}).Decode(export)
+	suite.NoError(err)
+	suite.Equal(models.ExpiredDataExportStatus, export.Status)
+
+	err = suite.DataExportsCollection.FindOne(context.Background(), bson.M{"_id": recentId}).Decode(export)
+	suite.NoError(err)
+	suite.Equal(models.CompletedDataExportStatus, export.Status)
+}
+
+func TestProcessDataExportsTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessDataExportsTestSuite))
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReconcileCountersTestSuite struct {
+	suite.Suite
+	CommentID          primitive.ObjectID
+	CommentsCollection *mongo.Collection
+	FollowsCollection  *mongo.Collection
+	PostID             primitive.ObjectID
+	PostsCollection    *mongo.Collection
+	RepliesCollection  *mongo.Collection
+	UserID             primitive.ObjectID
+	UsersCollection    *mongo.Collection
+}
+
+func (suite *ReconcileCountersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.CommentsCollection = services.GetMongoDBCollection(config.CommentsCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.RepliesCollection = services.GetMongoDBCollection(config.RepliesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *ReconcileCountersTestSuite) SetupTest() {
+	result, err := mocks.ReconcileCounters()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CommentID = result.CommentID
+	suite.PostID = result.PostID
+	suite.UserID = result.UserID
+}
+
+func (suite *ReconcileCountersTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.CommentsCollection, suite.FollowsCollection, suite.PostsCollection, suite.RepliesCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *ReconcileCountersTestSuite) Test_ReportsDiscrepanciesWithoutFixing() {
+	report, err := jobs.ReconcileCounters(context.Background(), false)
+	suite.NoError(err)
+	suite.Len(report.Discrepancies, 4)
+	suite.Equal(int64(0), report.Fixed)
+
+	user := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID}).Decode(user)
+	suite.NoError(err)
+	suite.Equal(3, user.FollowersCount)
+	suite.Equal(-1, user.PostsCount)
+}
+
+func (suite *ReconcileCountersTestSuite) Test_FixesDiscrepancies() {
+	report, err := jobs.ReconcileCounters(context.Background(), true)
+	suite.NoError(err)
+	suite.Len(report.Discrepancies, 4)
+	suite.Equal(int64(4), report.Fixed)
+
+	user := &models.User{}
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID}).Decode(user)
+	suite.NoError(err)
+	suite.Equal(1, user.FollowersCount)
+	suite.Equal(1, user.PostsCount)
+
+	post := &models.Post{}
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID}).Decode(post)
+	suite.NoError(err)
+	suite.Equal(1, post.CommentsCount)
+	suite.Equal(1, post.RepliesCount)
+
+	comment := &models.Comment{}
+	err = suite.CommentsCollection.FindOne(context.Background(), bson.M{"_id": suite.CommentID}).Decode(comment)
+	suite.NoError(err)
+	suite.Equal(1, comment.RepliesCount)
+
+	report, err = jobs.ReconcileCounters(context.Background(), false)
+	suite.NoError(err)
+	suite.Empty(report.Discrepancies)
+}
+
+func TestReconcileCountersTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconcileCountersTestSuite))
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/jobs"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+	JobLeasesCollection *mongo.Collection
+	Name                string
+	Runs                int64
+}
+
+func (suite *ScheduleTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.JobLeasesCollection = services.GetMongoDBCollection(config.JobLeasesCollection)
+}
+
+func (suite *ScheduleTestSuite) SetupTest() {
+	suite.Name = "Test job"
+	atomic.StoreInt64(&suite.Runs, 0)
+}
+
+func (suite *ScheduleTestSuite) TearDownTest() {
+	_, err := suite.JobLeasesCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Schedule runs the job every 10ms for 100ms
+func (suite *ScheduleTestSuite) Schedule() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs.Schedule(ctx, 10*time.Millisecond, suite.Name, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt64(&suite.Runs, 1)
+		return nil, nil
+	})
+	time.Sleep(100 * time.Millisecond)
+}
+
+func (suite *ScheduleTestSuite) InsertLease(expiresAt time.Time) {
+	lease := bson.M{"_id": suite.Name, "expiresAt": expiresAt, "holderId": primitive.NewObjectID()}
+	_, err := suite.JobLeasesCollection.InsertOne(context.Background(), lease)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *ScheduleTestSuite) Test_RunsTheJobWhileHoldingTheLease() {
+	suite.Schedule()
+
+	suite.Greater(atomic.LoadInt64(&suite.Runs), int64(1))
+}
+
+func (suite *ScheduleTestSuite) Test_DoesNotRunTheJobWhileAnotherInstanceHoldsTheLease() {
+	suite.InsertLease(time.Now().Add(time.Hour))
+	suite.Schedule()
+
+	suite.Zero(atomic.LoadInt64(&suite.Runs))
+}
+
+func (suite *ScheduleTestSuite) Test_TakesOverAnExpiredLease() {
+	suite.InsertLease(time.Now().Add(-time.Minute))
+	suite.Schedule()
+
+	suite.Greater(atomic.LoadInt64(&suite.Runs), int64(0))
+}
+
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetNotificationsTestSuite struct {
+	suite.Suite
+	NotificationsCollection *mongo.Collection
+	ResponseBody            bson.M
+	Token                   string
+	UsersCollection         *mongo.Collection
+}
+
+func (suite *GetNotificationsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.NotificationsCollection = services.GetMongoDBCollection(config.NotificationsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetNotificationsTestSuite) SetupTest() {
+	suite.ResponseBody = bson.M{}
+
+	token, err := mocks.GetNotifications()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = token
+}
+
+func (suite *GetNotificationsTestSuite) ExecuteRequest(method, url string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetNotificationsTestSuite) TearDownTest() {
+	_, err := suite.NotificationsCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = suite.UsersCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *GetNotificationsTestSuite) Test_SucceedsWithAggregatedNotifications() {
+	response, err := suite.ExecuteRequest(http.MethodGet, "/notifications")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	notification, _ := items[0].(map[string]interface{})
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Len(items, 1)
+	suite.Equal(suite.ResponseBody["unreadCount"], float64(1))
+	suite.Equal(notification["actorsCount"], float64(2))
+	suite.Len(notification["actors"], 2)
+}
+
+func (suite *GetNotificationsTestSuite) Test_SucceedsAfterReadingAll() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "/notifications/read")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+
+	response, err = suite.ExecuteRequest(http.MethodGet, "/notifications")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Equal(suite.ResponseBody["unreadCount"], float64(0))
+}
+
+// Grouping another actor into an older notification must not move it out of the pages left to read
+func (suite *GetNotificationsTestSuite) Test_SucceedsWithCursorWhileActorsAreGrouped() {
+	authUser, firstActor := &models.User{}, &models.User{}
+	err := suite.UsersCollection.FindOne(context.Background(), bson.M{"username": "authuser"}).Decode(authUser)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"username": "firstactor"}).Decode(firstActor)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = models.CreateNotification(context.Background(), authUser.ID, firstActor.ID, models.ReplyNotification, primitive.NewObjectID())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodGet, "/notifications?limit=1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	notification, _ := items[0].(map[string]interface{})
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal(models.ReplyNotification, notification["type"])
+
+	filter := bson.M{"userId": authUser.ID, "type": models.CommentNotification}
+	comment := &models.Notification{}
+	err = suite.NotificationsCollection.FindOne(context.Background(), filter).Decode(comment)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = models.CreateNotification(context.Background(), authUser.ID, primitive.NewObjectID(), models.CommentNotification, comment.EntityID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err = suite.ExecuteRequest(http.MethodGet, fmt.Sprintf("/notifications?limit=1&cursor=%v", suite.ResponseBody["nextCursor"]))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	items, _ = suite.ResponseBody["items"].([]interface{})
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Len(items, 1)
+	notification, _ = items[0].(map[string]interface{})
+	suite.Equal(models.CommentNotification, notification["type"])
+}
+
+func (suite *GetNotificationsTestSuite) Test_FailsIfTokenIsMissing() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest(http.MethodGet, "/notifications")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusUnauthorized)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetNotificationsTestSuite(t *testing.T) {
+	suite.Run(t, new(GetNotificationsTestSuite))
+}
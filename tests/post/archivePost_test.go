@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ArchivePostTestSuite struct {
+	suite.Suite
+	Action              string
+	CommentsCollection  *mongo.Collection
+	PostID              string
+	PostsCollection     *mongo.Collection
+	ResponseBody        bson.M
+	TimelinesCollection *mongo.Collection
+	Token               string
+	UserID              primitive.ObjectID
+	UsersCollection     *mongo.Collection
+}
+
+func (suite *ArchivePostTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.CommentsCollection = services.GetMongoDBCollection(config.CommentsCollection)
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.TimelinesCollection = services.GetMongoDBCollection(config.TimelinesCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *ArchivePostTestSuite) SetupTest() {
+	result, err := mocks.ArchivePost()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Action = "archive"
+	suite.PostID = result.PostID.Hex()
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *ArchivePostTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/posts/"+suite.PostID+"/"+suite.Action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *ArchivePostTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.CommentsCollection, suite.PostsCollection, suite.TimelinesCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *ArchivePostTestSuite) FindUser() *models.User {
+	user := &models.User{}
+	err := suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID}).Decode(user)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return user
+}
+
+func (suite *ArchivePostTestSuite) Test_Succeeds() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	postId, err := primitive.ObjectIDFromHex(suite.PostID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	post := &models.Post{}
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": postId}).Decode(post)
+	suite.NoError(err)
+	suite.NotNil(post.ArchivedAt)
+	suite.Equal(1, post.CommentsCount)
+
+	count, err := suite.CommentsCollection.CountDocuments(context.Background(), bson.M{"postId": postId})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	count, err = suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"postId": postId})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	user := suite.FindUser()
+	suite.Equal(0, user.PostsCount)
+	suite.Len(user.Posts, 0)
+}
+
+func (suite *ArchivePostTestSuite) Test_SucceedsIfPostIsUnarchived() {
+	for _, action := range []string{"archive", "unarchive", "unarchive"} {
+		suite.Action = action
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	postId, err := primitive.ObjectIDFromHex(suite.PostID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.PostsCollection.CountDocuments(context.Background(), bson.M{"_id": postId, "archivedAt": nil})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	count, err = suite.TimelinesCollection.CountDocuments(context.Background(), bson.M{"postId": postId, "userId": suite.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	user := suite.FindUser()
+	suite.Equal(1, user.PostsCount)
+	suite.Len(user.Posts, 1)
+}
+
+func (suite *ArchivePostTestSuite) Test_FailsIfPostIdIsInvalid() {
+	suite.PostID = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *ArchivePostTestSuite) Test_FailsIfPostNotFound() {
+	suite.PostID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *ArchivePostTestSuite) Test_FailsIfUserNotOwnerOfPost() {
+	user := models.User{ID: primitive.NewObjectID(), Email: "other@gmail.com", Username: "other"}
+	_, err := suite.UsersCollection.InsertOne(context.Background(), user)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token, err = user.GenerateAccessToken()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestArchivePostTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchivePostTestSuite))
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
@@ -71,6 +72,25 @@ func (suite *DeletePostTestSuite) ExecuteRequest() (*httptest.ResponseRecorder,
 	return response, nil
 }
 
+func (suite *DeletePostTestSuite) ExecuteRestoreRequest() (*httptest.ResponseRecorder, error) {
+	suite.ResponseBody = bson.M{}
+	request, err := http.NewRequest(http.MethodPost, "/posts/"+suite.PostID.Hex()+"/restore", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 func (suite *DeletePostTestSuite) TearDownTest() {
 	_, err := suite.CommentsCollection.DeleteMany(context.Background(), bson.M{})
 	if err != nil {
@@ -100,13 +120,13 @@ func (suite *DeletePostTestSuite) Test_Succeeds() {
 	}
 
 	err = suite.CommentsCollection.FindOne(context.Background(), bson.M{"postId": suite.PostID}).Err()
-	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	suite.NoError(err)
 
 	err = suite.RepliesCollection.FindOne(context.Background(), bson.M{"postId": suite.PostID}).Err()
-	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	suite.NoError(err)
 
-	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID}).Err()
-	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID, "deletedAt": bson.M{"$ne": nil}, "deletedBy": suite.UserID}).Err()
+	suite.NoError(err)
 
 	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID, "posts": bson.M{"$size": 0}}).Err()
 	suite.NoError(err)
@@ -115,6 +135,66 @@ func (suite *DeletePostTestSuite) Test_Succeeds() {
 	suite.Contains(suite.ResponseBody, "message")
 }
 
+func (suite *DeletePostTestSuite) Test_SucceedsAndCanBeRestored() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	response, err = suite.ExecuteRestoreRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.PostsCollection.FindOne(context.Background(), bson.M{"_id": suite.PostID, "deletedAt": nil}).Err()
+	suite.NoError(err)
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID, "posts._id": suite.PostID}).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeletePostTestSuite) Test_FailsToRestoreIfDeletedBeforeRetention() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	deletedAt := time.Now().Add(-config.DeletedContentRetention - time.Hour)
+	_, err = suite.PostsCollection.UpdateByID(context.Background(), suite.PostID, bson.M{"$set": bson.M{"deletedAt": deletedAt}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err = suite.ExecuteRestoreRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeletePostTestSuite) Test_FailsIfPostIsAlreadyDeleted() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	response, err = suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
 func (suite *DeletePostTestSuite) Test_FailsIfPostIdIsInvalid() {
 	suite.InvalidID = "invalid"
 
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
@@ -25,6 +26,7 @@ type GetPostTestSuite struct {
 	PostID          primitive.ObjectID
 	PostsCollection *mongo.Collection
 	ResponseBody    bson.M
+	Token           string
 	UserID          primitive.ObjectID
 	UsersCollection *mongo.Collection
 }
@@ -40,6 +42,7 @@ func (suite *GetPostTestSuite) SetupTest() {
 	suite.UserID = primitive.NewObjectID()
 	suite.ResponseBody = bson.M{}
 	suite.InvalidID = ""
+	suite.Token = ""
 
 	post := models.Post{
 		ID:       suite.PostID,
@@ -70,6 +73,10 @@ func (suite *GetPostTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, err
 		return nil, err
 	}
 
+	if suite.Token != "" {
+		request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	}
+
 	response := httptest.NewRecorder()
 	router := routes.SetupRouter()
 	router.ServeHTTP(response, request)
@@ -90,6 +97,13 @@ func (suite *GetPostTestSuite) TearDownTest() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	collections := []string{config.BlocksCollection, config.CloseFriendsCollection, config.FollowsCollection}
+	for _, name := range collections {
+		_, err = services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 func (suite *GetPostTestSuite) Test_Succeeds() {
@@ -143,6 +157,125 @@ func (suite *GetPostTestSuite) Test_FailsIfUserNotFound() {
 	suite.Contains(suite.ResponseBody, "message")
 }
 
+func (suite *GetPostTestSuite) Test_FailsIfAccountIsPrivate() {
+	_, err := suite.UsersCollection.UpdateByID(context.Background(), suite.UserID, bson.M{"$set": bson.M{"isPrivate": true}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusForbidden)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetPostTestSuite) Test_FailsIfViewerIsBlocked() {
+	viewer := models.User{ID: primitive.NewObjectID(), Email: "viewer@gmail.com", Username: "viewer"}
+	token, err := viewer.GenerateAccessToken()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	block := models.Block{ID: primitive.NewObjectID(), BlockerID: suite.UserID, UserID: viewer.ID}
+	_, err = services.GetMongoDBCollection(config.BlocksCollection).InsertOne(context.Background(), block)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = token
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetPostTestSuite) SetAudience(audience string) {
+	_, err := suite.PostsCollection.UpdateByID(context.Background(), suite.PostID, bson.M{"$set": bson.M{"audience": audience}})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *GetPostTestSuite) Test_SucceedsIfViewerIsCloseFriend() {
+	suite.SetAudience(models.CloseFriendsPostAudience)
+	viewer := models.User{ID: primitive.NewObjectID(), Email: "viewer@gmail.com", Username: "viewer"}
+	token, err := viewer.GenerateAccessToken()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = models.AddCloseFriend(context.Background(), suite.UserID, viewer.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = token
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Contains(suite.ResponseBody, "post")
+}
+
+func (suite *GetPostTestSuite) Test_FailsIfViewerNotCloseFriend() {
+	suite.SetAudience(models.CloseFriendsPostAudience)
+	viewer := models.User{ID: primitive.NewObjectID(), Email: "viewer@gmail.com", Username: "viewer"}
+	token, err := viewer.GenerateAccessToken()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	follow := models.Follow{ID: primitive.NewObjectID(), CreatedAt: time.Now(), FolloweeID: suite.UserID, FollowerID: viewer.ID}
+	_, err = services.GetMongoDBCollection(config.FollowsCollection).InsertOne(context.Background(), follow)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = token
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetPostTestSuite) Test_FailsIfAudienceIsFollowersAndViewerIsAnonymous() {
+	suite.SetAudience(models.FollowersPostAudience)
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetPostTestSuite) Test_FailsIfPostIsArchived() {
+	_, err := suite.PostsCollection.UpdateByID(context.Background(), suite.PostID, bson.M{"$set": bson.M{"archivedAt": time.Now()}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
 func TestGetPostTestSuite(t *testing.T) {
 	suite.Run(t, new(GetPostTestSuite))
 }
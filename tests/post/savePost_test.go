@@ -7,10 +7,13 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
 	"github.com/stretchr/testify/suite"
@@ -101,6 +104,65 @@ func (suite *SavePostTestSuite) Test_Succeeds() {
 	suite.Contains(suite.ResponseBody, "message")
 }
 
+func (suite *SavePostTestSuite) Test_SucceedsIfPostIsAlreadySaved() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "savedPosts": suite.PostID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	err = suite.UserDetailsCollection.FindOne(context.Background(), bson.M{"userId": suite.UserID, "savedPostsCount": 1}).Err()
+	suite.NoError(err)
+}
+
+func (suite *SavePostTestSuite) Test_ConcurrentSavesStoreThePostOnce() {
+	waitGroup := sync.WaitGroup{}
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			errs <- models.SavePost(context.Background(), suite.UserID, suite.PostID)
+		}()
+	}
+	waitGroup.Wait()
+	close(errs)
+
+	for err := range errs {
+		suite.NoError(err)
+	}
+
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "savedPosts": suite.PostID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *SavePostTestSuite) Test_FailsIfPostIsArchived() {
+	_, err := suite.PostsCollection.UpdateByID(context.Background(), suite.PostID, bson.M{"$set": bson.M{"archivedAt": time.Now()}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "savedPosts": suite.PostID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+}
+
 func (suite *SavePostTestSuite) Test_FailsIfPostIdIsInvalid() {
 	suite.InvalidID = "invalid"
 
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UnsavePostTestSuite struct {
+	suite.Suite
+	CollectionID                   primitive.ObjectID
+	PostID                         string
+	PostsCollection                *mongo.Collection
+	ResponseBody                   bson.M
+	SavedCollectionPostsCollection *mongo.Collection
+	SavedCollectionsCollection     *mongo.Collection
+	SavedPostID                    primitive.ObjectID
+	Token                          string
+	UserDetailsCollection          *mongo.Collection
+	UserID                         primitive.ObjectID
+	UsersCollection                *mongo.Collection
+}
+
+func (suite *UnsavePostTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.SavedCollectionPostsCollection = services.GetMongoDBCollection(config.SavedCollectionPostsCollection)
+	suite.SavedCollectionsCollection = services.GetMongoDBCollection(config.SavedCollectionsCollection)
+	suite.UserDetailsCollection = services.GetMongoDBCollection(config.UserDetailsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *UnsavePostTestSuite) SetupTest() {
+	result, err := mocks.SavedCollections()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.CollectionID = result.CollectionID
+	suite.PostID = result.SavedPostID.Hex()
+	suite.ResponseBody = bson.M{}
+	suite.SavedPostID = result.SavedPostID
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+}
+
+func (suite *UnsavePostTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/posts/"+suite.PostID+"/unsave", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *UnsavePostTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{
+		suite.PostsCollection,
+		suite.SavedCollectionPostsCollection,
+		suite.SavedCollectionsCollection,
+		suite.UserDetailsCollection,
+		suite.UsersCollection,
+	}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *UnsavePostTestSuite) Test_RemovesThePostFromSavedPostsAndCollections() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	count, err := suite.UserDetailsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID, "savedPosts": suite.SavedPostID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	err = suite.UserDetailsCollection.FindOne(context.Background(), bson.M{"userId": suite.UserID, "savedPostsCount": 0}).Err()
+	suite.NoError(err)
+
+	count, err = suite.SavedCollectionPostsCollection.CountDocuments(context.Background(), bson.M{"posts": suite.SavedPostID})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	collection := &models.SavedCollection{}
+	err = suite.SavedCollectionsCollection.FindOne(context.Background(), bson.M{"_id": suite.CollectionID}).Decode(collection)
+	suite.NoError(err)
+	suite.Equal(0, collection.PostsCount)
+}
+
+func (suite *UnsavePostTestSuite) Test_FailsIfPostIdIsInvalid() {
+	suite.PostID = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *UnsavePostTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestUnsavePostTestSuite(t *testing.T) {
+	suite.Run(t, new(UnsavePostTestSuite))
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetStoriesFeedTestSuite struct {
+	suite.Suite
+	Collections  []*mongo.Collection
+	ResponseBody bson.M
+	Token        string
+}
+
+func (suite *GetStoriesFeedTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	collectionNames := []string{
+		config.FollowsCollection,
+		config.StoriesCollection,
+		config.StoryViewsCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collectionNames {
+		suite.Collections = append(suite.Collections, services.GetMongoDBCollection(name))
+	}
+}
+
+func (suite *GetStoriesFeedTestSuite) SetupTest() {
+	result, err := mocks.GetStoriesFeed()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = result.Token
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *GetStoriesFeedTestSuite) ExecuteRequest(query string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, "/stories/feed"+query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetStoriesFeedTestSuite) TearDownTest() {
+	for _, collection := range suite.Collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetStoriesFeedTestSuite) Usernames() []string {
+	usernames := []string{}
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	for _, item := range items {
+		group, _ := item.(map[string]interface{})
+		user, _ := group["user"].(map[string]interface{})
+		usernames = append(usernames, user["username"].(string))
+	}
+
+	return usernames
+}
+
+func (suite *GetStoriesFeedTestSuite) Test_PagesThroughAuthorsWithACursor() {
+	response, err := suite.ExecuteRequest("?limit=2")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal([]string{"author0", "author1"}, suite.Usernames())
+	suite.Equal(true, suite.ResponseBody["hasNextPage"])
+
+	items, _ := suite.ResponseBody["items"].([]interface{})
+	group, _ := items[0].(map[string]interface{})
+	suite.Equal(false, group["hasUnseen"])
+	suite.NotContains(group, "_id")
+	group, _ = items[1].(map[string]interface{})
+	suite.Equal(true, group["hasUnseen"])
+
+	nextCursor, _ := suite.ResponseBody["nextCursor"].(string)
+	suite.NotEmpty(nextCursor)
+
+	response, err = suite.ExecuteRequest("?limit=2&cursor=" + nextCursor)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal([]string{"author2"}, suite.Usernames())
+	suite.Equal(false, suite.ResponseBody["hasNextPage"])
+}
+
+func TestGetStoriesFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(GetStoriesFeedTestSuite))
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetStoryViewersTestSuite struct {
+	suite.Suite
+	ResponseBody         bson.M
+	StoriesCollection    *mongo.Collection
+	StoryID              string
+	StoryViewsCollection *mongo.Collection
+	Token                string
+	UsersCollection      *mongo.Collection
+	ViewerToken          string
+}
+
+func (suite *GetStoryViewersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.StoriesCollection = services.GetMongoDBCollection(config.StoriesCollection)
+	suite.StoryViewsCollection = services.GetMongoDBCollection(config.StoryViewsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetStoryViewersTestSuite) SetupTest() {
+	result, err := mocks.CreateStory()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.StoryID = result.StoryID.Hex()
+	suite.Token = result.Token
+	suite.ViewerToken = result.ViewerToken
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *GetStoryViewersTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, "/stories/"+suite.StoryID+"/viewers", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetStoryViewersTestSuite) TearDownTest() {
+	for _, collection := range []*mongo.Collection{suite.StoriesCollection, suite.StoryViewsCollection, suite.UsersCollection} {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetStoryViewersTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Len(suite.ResponseBody["items"], 1)
+}
+
+func (suite *GetStoryViewersTestSuite) Test_FailsIfNotAuthor() {
+	suite.Token = suite.ViewerToken
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusForbidden)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetStoryViewersTestSuite) Test_FailsWithInvalidId() {
+	suite.StoryID = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusBadRequest)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetStoryViewersTestSuite(t *testing.T) {
+	suite.Run(t, new(GetStoryViewersTestSuite))
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BlockUserTestSuite struct {
+	suite.Suite
+	BlockedID         primitive.ObjectID
+	BlocksCollection  *mongo.Collection
+	FollowsCollection *mongo.Collection
+	ResponseBody      bson.M
+	Token             string
+	UserID            string
+	UsersCollection   *mongo.Collection
+	ViewerID          primitive.ObjectID
+}
+
+func (suite *BlockUserTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.BlocksCollection = services.GetMongoDBCollection(config.BlocksCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *BlockUserTestSuite) SetupTest() {
+	result, err := mocks.BlockUser()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.BlockedID = result.BlockedID
+	suite.ResponseBody = bson.M{}
+	suite.Token = result.Token
+	suite.UserID = result.UserID.Hex()
+	suite.ViewerID = result.ViewerID
+}
+
+func (suite *BlockUserTestSuite) ExecuteRequest(action string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/users/"+suite.UserID+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *BlockUserTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.BlocksCollection, suite.FollowsCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *BlockUserTestSuite) HasBlocked(blockerId, userId primitive.ObjectID) (bool, error) {
+	count, err := suite.BlocksCollection.CountDocuments(context.Background(), bson.M{"blockerId": blockerId, "userId": userId})
+	return count > 0, err
+}
+
+func (suite *BlockUserTestSuite) Test_BlockRemovesFollowsInBothDirections() {
+	response, err := suite.ExecuteRequest("block")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+
+	userId, err := primitive.ObjectIDFromHex(suite.UserID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	blocked, err := suite.HasBlocked(suite.ViewerID, userId)
+	suite.NoError(err)
+	suite.True(blocked)
+
+	count, err := suite.FollowsCollection.CountDocuments(context.Background(), bson.M{})
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	for _, id := range []primitive.ObjectID{suite.ViewerID, userId} {
+		user := &models.User{}
+		err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(user)
+		suite.NoError(err)
+		suite.Equal(0, user.FollowersCount)
+		suite.Equal(0, user.FollowingCount)
+	}
+}
+
+func (suite *BlockUserTestSuite) Test_BlockIsIdempotent() {
+	for i := 0; i < 2; i++ {
+		response, err := suite.ExecuteRequest("block")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusOK, response.Code)
+	}
+
+	count, err := suite.BlocksCollection.CountDocuments(context.Background(), bson.M{"blockerId": suite.ViewerID})
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+}
+
+func (suite *BlockUserTestSuite) Test_UnblockRemovesTheBlock() {
+	suite.UserID = suite.BlockedID.Hex()
+
+	response, err := suite.ExecuteRequest("unblock")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	blocked, err := suite.HasBlocked(suite.ViewerID, suite.BlockedID)
+	suite.NoError(err)
+	suite.False(blocked)
+}
+
+func (suite *BlockUserTestSuite) Test_UnblockKeepsBlocksByTheOtherUser() {
+	response, err := suite.ExecuteRequest("block")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	userId, err := primitive.ObjectIDFromHex(suite.UserID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token, err = (&models.User{ID: userId}).GenerateAccessToken()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.UserID = suite.ViewerID.Hex()
+	response, err = suite.ExecuteRequest("unblock")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	blocked, err := suite.HasBlocked(suite.ViewerID, userId)
+	suite.NoError(err)
+	suite.True(blocked)
+}
+
+func (suite *BlockUserTestSuite) Test_FailsIfUserBlocksThemselves() {
+	suite.UserID = suite.ViewerID.Hex()
+
+	response, err := suite.ExecuteRequest("block")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *BlockUserTestSuite) Test_FailsIfUserIdIsInvalid() {
+	suite.UserID = "invalid"
+
+	for _, action := range []string{"block", "unblock"} {
+		response, err := suite.ExecuteRequest(action)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusBadRequest, response.Code)
+		suite.Contains(suite.ResponseBody, "message")
+	}
+}
+
+func (suite *BlockUserTestSuite) Test_FailsIfUserNotFound() {
+	suite.UserID = primitive.NewObjectID().Hex()
+
+	response, err := suite.ExecuteRequest("block")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *BlockUserTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	for _, action := range []string{"block", "unblock"} {
+		response, err := suite.ExecuteRequest(action)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		suite.Equal(http.StatusUnauthorized, response.Code)
+		suite.Contains(suite.ResponseBody, "message")
+	}
+}
+
+func TestBlockUserTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockUserTestSuite))
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DeleteUserTestSuite struct {
+	suite.Suite
+	Password        string
+	ResponseBody    bson.M
+	Token           string
+	UserID          primitive.ObjectID
+	UsersCollection *mongo.Collection
+}
+
+func (suite *DeleteUserTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *DeleteUserTestSuite) SetupTest() {
+	result, err := mocks.DeleteAccount()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Password = result.Password
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *DeleteUserTestSuite) ExecuteRequest(method, path string, requestBody bson.M) (*httptest.ResponseRecorder, error) {
+	requestBodyBytes, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, path, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *DeleteUserTestSuite) TearDownTest() {
+	collections := []string{
+		config.CommentsCollection,
+		config.FollowsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.SavedCollectionPostsCollection,
+		config.SavedCollectionsCollection,
+		config.UserDetailsCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collections {
+		_, err := services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *DeleteUserTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{"password": suite.Password})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := bson.M{"_id": suite.UserID, "deactivatedAt": bson.M{"$ne": nil}, "deletionRequestedAt": bson.M{"$ne": nil}}
+	err = suite.UsersCollection.FindOne(context.Background(), filter).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusAccepted, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteUserTestSuite) Test_FailsIfPasswordDoesNotMatch() {
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{"password": "notmatch"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID, "deletionRequestedAt": nil}).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteUserTestSuite) Test_FailsIfPasswordIsMissing() {
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "password")
+}
+
+func (suite *DeleteUserTestSuite) Test_DeactivateSucceeds() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "/users/me/deactivate", bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := bson.M{"_id": suite.UserID, "deactivatedAt": bson.M{"$ne": nil}, "deletionRequestedAt": nil}
+	err = suite.UsersCollection.FindOne(context.Background(), filter).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+// The cookie is cleared on the current device only, so a token copied before must not authorize anything
+func (suite *DeleteUserTestSuite) Test_RejectsTokenIssuedBeforeDeletion() {
+	_, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{"password": suite.Password})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPost, "/users/me/deactivate", bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteUserTestSuite) Test_RejectsTokenIssuedBeforeDeactivation() {
+	_, err := suite.ExecuteRequest(http.MethodPost, "/users/me/deactivate", bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{"password": suite.Password})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"_id": suite.UserID, "deletionRequestedAt": nil}).Err()
+	suite.NoError(err)
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *DeleteUserTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest(http.MethodDelete, "/users/me", bson.M{"password": suite.Password})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestDeleteUserTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteUserTestSuite))
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetBlockedUsersTestSuite struct {
+	suite.Suite
+	BlockedID         primitive.ObjectID
+	BlocksCollection  *mongo.Collection
+	FollowsCollection *mongo.Collection
+	ResponseBody      bson.M
+	Token             string
+	UsersCollection   *mongo.Collection
+}
+
+func (suite *GetBlockedUsersTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.BlocksCollection = services.GetMongoDBCollection(config.BlocksCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetBlockedUsersTestSuite) SetupTest() {
+	result, err := mocks.BlockUser()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.BlockedID = result.BlockedID
+	suite.ResponseBody = bson.M{}
+	suite.Token = result.Token
+}
+
+func (suite *GetBlockedUsersTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, "/users/me/blocked", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetBlockedUsersTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.BlocksCollection, suite.FollowsCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetBlockedUsersTestSuite) Test_ReturnsOnlyTheUsersTheViewerBlocked() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+	suite.Len(items, 1)
+
+	block, _ := items[0].(map[string]interface{})
+	user, _ := block["user"].(map[string]interface{})
+	suite.Equal(suite.BlockedID.Hex(), user["_id"])
+	suite.Equal("blocked", user["username"])
+	suite.NotContains(block, "blockerId")
+	suite.NotContains(user, "email")
+}
+
+func (suite *GetBlockedUsersTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetBlockedUsersTestSuite(t *testing.T) {
+	suite.Run(t, new(GetBlockedUsersTestSuite))
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
@@ -23,26 +24,30 @@ import (
 
 type GetUserHomePostsTestSuite struct {
 	suite.Suite
-	ResponseBody          bson.M
-	Limit                 string
-	Skip                  string
-	Username              string
-	Token                 string
-	PostsCollection       *mongo.Collection
-	UsersCollection       *mongo.Collection
-	UserDetailsCollection *mongo.Collection
+	ResponseBody        bson.M
+	Cursor              string
+	Limit               string
+	Skip                string
+	Username            string
+	Token               string
+	PostsCollection     *mongo.Collection
+	TimelinesCollection *mongo.Collection
+	UsersCollection     *mongo.Collection
+	FollowsCollection   *mongo.Collection
 }
 
 func (suite *GetUserHomePostsTestSuite) SetupSuite() {
 	services.CreateMongoDBConnection()
 	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
 	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
-	suite.UserDetailsCollection = services.GetMongoDBCollection(config.UserDetailsCollection)
+	suite.TimelinesCollection = services.GetMongoDBCollection(config.TimelinesCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
 }
 
 func (suite *GetUserHomePostsTestSuite) SetupTest() {
 	suite.ResponseBody = bson.M{}
 	suite.Username = "testuser"
+	suite.Cursor = ""
 	suite.Skip = "2"
 	suite.Limit = "4"
 
@@ -55,7 +60,7 @@ func (suite *GetUserHomePostsTestSuite) SetupTest() {
 }
 
 func (suite *GetUserHomePostsTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/users/me/posts/home?skip=%v&limit=%v", suite.Skip, suite.Limit), nil)
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/users/me/posts/home?skip=%v&limit=%v&cursor=%v", suite.Skip, suite.Limit, suite.Cursor), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +84,12 @@ func (suite *GetUserHomePostsTestSuite) TearDownTest() {
 		log.Fatal(err)
 	}
 
-	_, err = suite.UserDetailsCollection.DeleteMany(context.Background(), bson.M{})
+	_, err = suite.TimelinesCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = suite.FollowsCollection.DeleteMany(context.Background(), bson.M{})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -100,7 +110,144 @@ func (suite *GetUserHomePostsTestSuite) Test_Succeeds() {
 	suite.NoError(err)
 
 	suite.Equal(response.Code, http.StatusOK)
-	suite.Len(suite.ResponseBody["posts"], length)
+	suite.Len(suite.ResponseBody["items"], length)
+	suite.Equal(suite.ResponseBody["hasNextPage"], false)
+}
+
+func (suite *GetUserHomePostsTestSuite) Test_SucceedsWithCursor() {
+	suite.Skip = ""
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Len(suite.ResponseBody["items"], 4)
+	suite.Equal(suite.ResponseBody["hasNextPage"], true)
+
+	suite.Cursor = fmt.Sprint(suite.ResponseBody["nextCursor"])
+	suite.ResponseBody = bson.M{}
+	response, err = suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Len(suite.ResponseBody["items"], 2)
+	suite.Equal(suite.ResponseBody["hasNextPage"], false)
+}
+
+// The followed user's posts are still in the timeline from before they crossed the limit
+func (suite *GetUserHomePostsTestSuite) Test_DoesNotDuplicatePostsOfUsersFannedOutOnRead() {
+	update := bson.M{"$set": bson.M{"followersCount": config.TimelineFanOutLimit + 1}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": "usertofollow"}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Skip = ""
+	suite.Limit = "10"
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+	suite.Len(items, 6)
+
+	ids := map[interface{}]bool{}
+	for _, item := range items {
+		ids[item.(map[string]interface{})["_id"]] = true
+	}
+	suite.Len(ids, 6)
+}
+
+// Posts written while the followed user was over the limit are not in the timeline
+func (suite *GetUserHomePostsTestSuite) Test_KeepsPostsOfUsersThatDroppedUnderTheLimit() {
+	update := bson.M{"$set": bson.M{"fanOutOnRead": true}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": "usertofollow"}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = suite.TimelinesCollection.DeleteMany(context.Background(), bson.M{"$expr": bson.M{"$ne": bson.A{"$authorId", "$userId"}}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Skip = ""
+	suite.Limit = "10"
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Len(suite.ResponseBody["items"], 6)
+}
+
+// The newest entries of the timeline are close friends posts the user is not in the audience of
+func (suite *GetUserHomePostsTestSuite) Test_PagesPastEntriesLeftOutByTheAudience() {
+	authUser, userToFollow := &models.User{}, &models.User{}
+	err := suite.UsersCollection.FindOne(context.Background(), bson.M{"username": "authuser"}).Decode(authUser)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = suite.UsersCollection.FindOne(context.Background(), bson.M{"username": "usertofollow"}).Decode(userToFollow)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	posts := []models.Post{}
+	for i := 0; i < config.TimelineScanFactor*2+1; i++ {
+		post := models.Post{Audience: models.CloseFriendsPostAudience}
+		post.NormalizeFields(userToFollow.ID)
+		post.CreatedAt = post.CreatedAt.Add(time.Hour)
+		posts = append(posts, post)
+	}
+
+	err = models.AddPostsToTimelines(context.Background(), bson.A{authUser.ID}, posts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Skip = ""
+	suite.Limit = "1"
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Len(suite.ResponseBody["items"], 0)
+	suite.Equal(true, suite.ResponseBody["hasNextPage"])
+
+	suite.Cursor = fmt.Sprint(suite.ResponseBody["nextCursor"])
+	suite.ResponseBody = bson.M{}
+	response, err = suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Len(suite.ResponseBody["items"], 1)
+	suite.Equal(true, suite.ResponseBody["hasNextPage"])
+}
+
+func (suite *GetUserHomePostsTestSuite) Test_FailsIfCursorIsInvalid() {
+	suite.Skip = ""
+	suite.Cursor = "invalid"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusBadRequest)
+	suite.Contains(suite.ResponseBody, "message")
 }
 
 func (suite *GetUserHomePostsTestSuite) Test_FailsIfLimitQueryValueIsInvalid() {
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetUserSavedPostsTestSuite struct {
+	suite.Suite
+	Collections    []*mongo.Collection
+	ResponseBody   bson.M
+	Token          string
+	VisiblePostIDs []primitive.ObjectID
+}
+
+func (suite *GetUserSavedPostsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	for _, name := range []string{config.BlocksCollection, config.FollowsCollection, config.PostsCollection, config.UserDetailsCollection, config.UsersCollection} {
+		suite.Collections = append(suite.Collections, services.GetMongoDBCollection(name))
+	}
+}
+
+func (suite *GetUserSavedPostsTestSuite) SetupTest() {
+	token, visiblePostIds, err := mocks.GetUserSavedPosts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.ResponseBody = bson.M{}
+	suite.Token = token
+	suite.VisiblePostIDs = visiblePostIds
+}
+
+func (suite *GetUserSavedPostsTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, "/users/me/posts/saved", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetUserSavedPostsTestSuite) TearDownTest() {
+	for _, collection := range suite.Collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetUserSavedPostsTestSuite) Test_LeavesOutPostsOfBlockedAndUnfollowedPrivateAuthors() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+
+	postIds := []string{}
+	for _, item := range items {
+		post, _ := item.(map[string]interface{})
+		postIds = append(postIds, post["_id"].(string))
+	}
+
+	expectedIds := []string{}
+	for _, id := range suite.VisiblePostIDs {
+		expectedIds = append(expectedIds, id.Hex())
+	}
+	suite.ElementsMatch(expectedIds, postIds)
+}
+
+func (suite *GetUserSavedPostsTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetUserSavedPostsTestSuite(t *testing.T) {
+	suite.Run(t, new(GetUserSavedPostsTestSuite))
+}
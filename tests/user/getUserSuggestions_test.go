@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetUserSuggestionsTestSuite struct {
+	suite.Suite
+	DismissedSuggestionsCollection *mongo.Collection
+	FollowsCollection              *mongo.Collection
+	ResponseBody                   bson.M
+	Token                          string
+	UsersCollection                *mongo.Collection
+}
+
+func (suite *GetUserSuggestionsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.DismissedSuggestionsCollection = services.GetMongoDBCollection(config.DismissedSuggestionsCollection)
+	suite.FollowsCollection = services.GetMongoDBCollection(config.FollowsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetUserSuggestionsTestSuite) SetupTest() {
+	token, err := mocks.GetUserSuggestions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = token
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *GetUserSuggestionsTestSuite) ExecuteRequest(method, path string) (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetUserSuggestionsTestSuite) TearDownTest() {
+	collections := []*mongo.Collection{suite.DismissedSuggestionsCollection, suite.FollowsCollection, suite.UsersCollection}
+	for _, collection := range collections {
+		_, err := collection.DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *GetUserSuggestionsTestSuite) GetSuggestedUsernames() []string {
+	response, err := suite.ExecuteRequest(http.MethodGet, "/users/me/suggestions")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	items, ok := suite.ResponseBody["items"].([]interface{})
+	suite.True(ok)
+
+	usernames := []string{}
+	for _, item := range items {
+		usernames = append(usernames, item.(map[string]interface{})["username"].(string))
+	}
+
+	return usernames
+}
+
+func (suite *GetUserSuggestionsTestSuite) Test_Succeeds() {
+	usernames := suite.GetSuggestedUsernames()
+	suite.Equal([]string{"mutual2", "mutual1", "follower", "popular"}, usernames)
+
+	items := suite.ResponseBody["items"].([]interface{})
+	suite.Equal("mutual", items[0].(map[string]interface{})["reason"])
+	suite.Equal(float64(2), items[0].(map[string]interface{})["mutualCount"])
+	suite.Equal("followsYou", items[2].(map[string]interface{})["reason"])
+	suite.Equal("popular", items[3].(map[string]interface{})["reason"])
+}
+
+func (suite *GetUserSuggestionsTestSuite) Test_DismissedSuggestionIsRemembered() {
+	user := &models.User{}
+	err := suite.UsersCollection.FindOne(context.Background(), bson.M{"username": "mutual2"}).Decode(user)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest(http.MethodPost, "/users/me/suggestions/"+user.ID.Hex()+"/dismiss")
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.Equal(http.StatusOK, response.Code)
+
+	usernames := suite.GetSuggestedUsernames()
+	suite.Equal([]string{"mutual1", "follower", "popular"}, usernames)
+}
+
+func (suite *GetUserSuggestionsTestSuite) Test_DismissFailsIfUserIdIsInvalid() {
+	response, err := suite.ExecuteRequest(http.MethodPost, "/users/me/suggestions/invalid/dismiss")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetUserSuggestionsTestSuite(t *testing.T) {
+	suite.Run(t, new(GetUserSuggestionsTestSuite))
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetUserTaggedPostsTestSuite struct {
+	suite.Suite
+	ResponseBody    bson.M
+	Username        string
+	PostsCollection *mongo.Collection
+	UsersCollection *mongo.Collection
+}
+
+func (suite *GetUserTaggedPostsTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.PostsCollection = services.GetMongoDBCollection(config.PostsCollection)
+	suite.UsersCollection = services.GetMongoDBCollection(config.UsersCollection)
+}
+
+func (suite *GetUserTaggedPostsTestSuite) SetupTest() {
+	suite.ResponseBody = bson.M{}
+
+	username, err := mocks.GetUserTaggedPosts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Username = username
+}
+
+func (suite *GetUserTaggedPostsTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/users/%v/posts/tagged", suite.Username), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *GetUserTaggedPostsTestSuite) TearDownTest() {
+	_, err := suite.PostsCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = suite.UsersCollection.DeleteMany(context.Background(), bson.M{})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (suite *GetUserTaggedPostsTestSuite) Test_SucceedsWithOnlyPostsTaggingTheUser() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Len(suite.ResponseBody["items"], 2)
+}
+
+func (suite *GetUserTaggedPostsTestSuite) Test_FailsIfAccountIsPrivate() {
+	update := bson.M{"$set": bson.M{"isPrivate": true}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": suite.Username}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetUserTaggedPostsTestSuite) Test_FailsIfUserIsDeactivated() {
+	update := bson.M{"$set": bson.M{"deactivatedAt": time.Now()}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": suite.Username}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *GetUserTaggedPostsTestSuite) Test_FailsIfUserNotFound() {
+	suite.Username = "unknownuser"
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusNotFound, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestGetUserTaggedPostsTestSuite(t *testing.T) {
+	suite.Run(t, new(GetUserTaggedPostsTestSuite))
+}
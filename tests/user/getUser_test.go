@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
 	"github.com/Ekenzy-101/Go-Gin-REST-API/helpers"
@@ -87,6 +88,40 @@ func (suite *GetUserTestSuite) Test_Succeeds() {
 	suite.Subset(list, subset)
 }
 
+func (suite *GetUserTestSuite) Test_SucceedsWithoutPostsIfAccountIsPrivate() {
+	update := bson.M{"$set": bson.M{"isPrivate": true, "posts": bson.A{bson.M{"caption": "Test"}}}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": suite.Username}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	user, _ := suite.ResponseBody["user"].(map[string]interface{})
+
+	suite.Equal(response.Code, http.StatusOK)
+	suite.Empty(user["posts"])
+}
+
+func (suite *GetUserTestSuite) Test_FailsIfUserIsDeactivated() {
+	update := bson.M{"$set": bson.M{"deactivatedAt": time.Now()}}
+	_, err := suite.UsersCollection.UpdateOne(context.Background(), bson.M{"username": suite.Username}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(response.Code, http.StatusNotFound)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
 func (suite *GetUserTestSuite) Test_FailsIfUserNotFound() {
 	suite.Username = "anotherusername"
 
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ekenzy-101/Go-Gin-REST-API/config"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/mocks"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/models"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/routes"
+	"github.com/Ekenzy-101/Go-Gin-REST-API/services"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RequestDataExportTestSuite struct {
+	suite.Suite
+	DataExportsCollection *mongo.Collection
+	ResponseBody          bson.M
+	Token                 string
+	UserID                primitive.ObjectID
+}
+
+func (suite *RequestDataExportTestSuite) SetupSuite() {
+	services.CreateMongoDBConnection()
+	suite.DataExportsCollection = services.GetMongoDBCollection(config.DataExportsCollection)
+}
+
+func (suite *RequestDataExportTestSuite) SetupTest() {
+	result, err := mocks.DeleteAccount()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Token = result.Token
+	suite.UserID = result.UserID
+	suite.ResponseBody = bson.M{}
+}
+
+func (suite *RequestDataExportTestSuite) ExecuteRequest() (*httptest.ResponseRecorder, error) {
+	request, err := http.NewRequest(http.MethodPost, "/users/me/export", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.AddCookie(&http.Cookie{Name: config.AccessTokenCookieName, Value: suite.Token})
+	response := httptest.NewRecorder()
+	router := routes.SetupRouter()
+	router.ServeHTTP(response, request)
+
+	suite.ResponseBody = bson.M{}
+	err = json.NewDecoder(response.Body).Decode(&suite.ResponseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (suite *RequestDataExportTestSuite) TearDownTest() {
+	collections := []string{
+		config.CommentsCollection,
+		config.DataExportsCollection,
+		config.FollowsCollection,
+		config.PostsCollection,
+		config.RepliesCollection,
+		config.SavedCollectionPostsCollection,
+		config.SavedCollectionsCollection,
+		config.UserDetailsCollection,
+		config.UsersCollection,
+	}
+	for _, name := range collections {
+		_, err := services.GetMongoDBCollection(name).DeleteMany(context.Background(), bson.M{})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (suite *RequestDataExportTestSuite) Test_Succeeds() {
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := bson.M{"userId": suite.UserID, "status": models.PendingDataExportStatus}
+	count, err := suite.DataExportsCollection.CountDocuments(context.Background(), filter)
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	suite.Equal(http.StatusAccepted, response.Code)
+	suite.Contains(suite.ResponseBody, "export")
+}
+
+func (suite *RequestDataExportTestSuite) Test_FailsIfAnExportIsPending() {
+	_, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.DataExportsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *RequestDataExportTestSuite) Test_FailsIfAnExportIsProcessing() {
+	_, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = models.ClaimDataExport(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := suite.DataExportsCollection.CountDocuments(context.Background(), bson.M{"userId": suite.UserID})
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func (suite *RequestDataExportTestSuite) Test_SucceedsIfPreviousExportIsCompleted() {
+	_, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	update := bson.M{"$set": bson.M{"status": models.CompletedDataExportStatus}}
+	_, err = suite.DataExportsCollection.UpdateMany(context.Background(), bson.M{"userId": suite.UserID}, update)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusAccepted, response.Code)
+	suite.Contains(suite.ResponseBody, "export")
+}
+
+func (suite *RequestDataExportTestSuite) Test_FailsIfUserNotLoggedIn() {
+	suite.Token = ""
+
+	response, err := suite.ExecuteRequest()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Contains(suite.ResponseBody, "message")
+}
+
+func TestRequestDataExportTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestDataExportTestSuite))
+}